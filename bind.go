@@ -0,0 +1,379 @@
+package configuration
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// tagName is struct tag used to set config key name for struct field
+	// `config:"name"` binds field to key name, `config:"-"` skips field
+	tagName = "config"
+	// defaultTagName is struct tag with value used when key is absent: `default:"30s"`
+	defaultTagName = "default"
+	// requiredTagName is struct tag making absent key an error: `required:"true"`
+	requiredTagName = "required"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// pathString makes human readable path for error messages
+func pathString(path []string) string {
+	if 0 == len(path) {
+		return "<root>"
+	}
+	return strings.Join(path, ".")
+}
+
+// binder decodes config values into go values and collects all problems found
+type binder struct {
+	errs []error
+}
+
+// fail adds field level error
+func (b *binder) fail(path []string, format string, a ...interface{}) {
+	b.errs = append(b.errs, NewConfigTypeMismatchError(pathString(path)+": "+fmt.Sprintf(format, a...)))
+}
+
+// unmarshalValue decodes config value into target which must be not nil pointer
+// path is used for error messages only
+func unmarshalValue(val interface{}, target interface{}, path []string) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return NewConfigUsageError("Unmarshal target must be not nil pointer")
+	}
+	b := &binder{}
+	b.bind(val, rv.Elem(), path)
+	if len(b.errs) > 0 {
+		return NewConfigBindError(b.errs)
+	}
+	return nil
+}
+
+// bind decodes val into rv
+func (b *binder) bind(val interface{}, rv reflect.Value, path []string) {
+	if nil == val {
+		// null values leave target untouched
+		return
+	}
+	switch rv.Type() {
+	case durationType:
+		b.bindDuration(val, rv, path)
+		return
+	case timeType:
+		b.bindTime(val, rv, path)
+		return
+	}
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			b.fail(path, "can not bind to non empty interface %v", rv.Type())
+			return
+		}
+		rv.Set(reflect.ValueOf(val))
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		b.bind(val, rv.Elem(), path)
+	case reflect.Struct:
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			b.fail(path, "expected object, got %T", val)
+			return
+		}
+		b.bindStruct(m, rv, path)
+	case reflect.Map:
+		b.bindMap(val, rv, path)
+	case reflect.Slice:
+		l, ok := val.([]interface{})
+		if !ok {
+			b.fail(path, "expected array, got %T", val)
+			return
+		}
+		s := reflect.MakeSlice(rv.Type(), len(l), len(l))
+		for i, item := range l {
+			b.bind(item, s.Index(i), appendPath(path, strconv.Itoa(i)))
+		}
+		rv.Set(s)
+	case reflect.Array:
+		l, ok := val.([]interface{})
+		if !ok {
+			b.fail(path, "expected array, got %T", val)
+			return
+		}
+		if len(l) != rv.Len() {
+			b.fail(path, "expected array of %d items, got %d", rv.Len(), len(l))
+			return
+		}
+		for i, item := range l {
+			b.bind(item, rv.Index(i), appendPath(path, strconv.Itoa(i)))
+		}
+	case reflect.String:
+		s, ok := val.(string)
+		if !ok {
+			b.fail(path, "expected string, got %T", val)
+			return
+		}
+		rv.SetString(s)
+	case reflect.Bool:
+		v, ok := val.(bool)
+		if !ok {
+			b.fail(path, "expected boolean, got %T", val)
+			return
+		}
+		rv.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, ok := numberValue(val); !ok {
+			b.fail(path, "expected integer, got %T", val)
+			return
+		}
+		n, whole, ok := exactInteger(val)
+		if !ok || !whole || !n.IsInt64() || rv.OverflowInt(n.Int64()) {
+			b.fail(path, "value %v does not fit %v", val, rv.Type())
+			return
+		}
+		rv.SetInt(n.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, ok := numberValue(val); !ok {
+			b.fail(path, "expected unsigned integer, got %T", val)
+			return
+		}
+		n, whole, ok := exactInteger(val)
+		if !ok || !whole || !n.IsUint64() || rv.OverflowUint(n.Uint64()) {
+			b.fail(path, "value %v does not fit %v", val, rv.Type())
+			return
+		}
+		rv.SetUint(n.Uint64())
+	case reflect.Float32, reflect.Float64:
+		f, ok := numberValue(val)
+		if !ok {
+			b.fail(path, "expected number, got %T", val)
+			return
+		}
+		if rv.OverflowFloat(f) {
+			b.fail(path, "value %v does not fit %v", f, rv.Type())
+			return
+		}
+		rv.SetFloat(f)
+	default:
+		b.fail(path, "can not bind to %v", rv.Type())
+	}
+}
+
+// bindStruct decodes object into struct fields
+func (b *binder) bindStruct(m map[string]interface{}, rv reflect.Value, path []string) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(tagName)
+		if "-" == tag {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Anonymous && "" == tag {
+			// embedded structs take their fields from the same object
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						if !fv.CanSet() {
+							// pointer to not exported struct can not be allocated there
+							continue
+						}
+						fv.Set(reflect.New(ft))
+					}
+					fv = fv.Elem()
+				}
+				b.bindStruct(m, fv, path)
+				continue
+			}
+		}
+		if "" != field.PkgPath {
+			// not exported
+			continue
+		}
+		name := tag
+		if "" == name {
+			name = field.Name
+		}
+		val, ok := lookupKey(m, name)
+		if !ok {
+			b.bindAbsent(field, fv, appendPath(path, name))
+			continue
+		}
+		b.bind(val, fv, appendPath(path, name))
+	}
+}
+
+// bindAbsent processes struct field which key is absent in config:
+// reports required fields, fills default values and goes into nested structs for their defaults
+func (b *binder) bindAbsent(field reflect.StructField, fv reflect.Value, path []string) {
+	if required, _ := strconv.ParseBool(field.Tag.Get(requiredTagName)); required {
+		b.errs = append(b.errs, NewConfigItemNotFound(pathString(path)+": required value is missing"))
+		return
+	}
+	if def, ok := field.Tag.Lookup(defaultTagName); ok {
+		val, err := defaultValue(def, fv.Type())
+		if err != nil {
+			b.fail(path, "wrong default %q: %v", def, err)
+			return
+		}
+		b.bind(val, fv, path)
+		return
+	}
+	if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+		b.bindStruct(map[string]interface{}{}, fv, path)
+	}
+}
+
+// defaultValue makes config value from default tag text for type t
+// slices take comma separated items
+func defaultValue(s string, t reflect.Type) (interface{}, error) {
+	if t == durationType || t == timeType {
+		return s, nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return defaultValue(s, t.Elem())
+	case reflect.String, reflect.Interface:
+		return s, nil
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return parseNumber(s)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	case reflect.Slice, reflect.Array:
+		l := []interface{}{}
+		if "" == s {
+			return l, nil
+		}
+		for _, item := range strings.Split(s, ",") {
+			v, err := defaultValue(strings.TrimSpace(item), t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	default:
+		return nil, fmt.Errorf("defaults are not supported for %v", t)
+	}
+}
+
+// bindMap decodes object into map with string keys
+func (b *binder) bindMap(val interface{}, rv reflect.Value, path []string) {
+	t := rv.Type()
+	if t.Key().Kind() != reflect.String {
+		b.fail(path, "can not bind to map with %v keys", t.Key())
+		return
+	}
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		b.fail(path, "expected object, got %T", val)
+		return
+	}
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(t, len(m)))
+	}
+	for k, item := range m {
+		ev := reflect.New(t.Elem()).Elem()
+		b.bind(item, ev, appendPath(path, k))
+		rv.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), ev)
+	}
+}
+
+// bindDuration decodes "30s" strings or numbers of seconds into time.Duration
+func (b *binder) bindDuration(val interface{}, rv reflect.Value, path []string) {
+	d, err := durationValue(val)
+	if err != nil {
+		b.fail(path, "%v", err)
+		return
+	}
+	rv.SetInt(int64(d))
+}
+
+// bindTime decodes time.Time values or RFC3339 strings into time.Time
+func (b *binder) bindTime(val interface{}, rv reflect.Value, path []string) {
+	switch v := val.(type) {
+	case time.Time:
+		rv.Set(reflect.ValueOf(v))
+	case string:
+		tm, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			b.fail(path, "%v", err)
+			return
+		}
+		rv.Set(reflect.ValueOf(tm))
+	default:
+		b.fail(path, "expected time, got %T", val)
+	}
+}
+
+// lookupKey finds key in object. exact match wins over case insensitive one
+func lookupKey(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// numberValue gives float64 of numeric config value
+func numberValue(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// exactInteger gives integer part of number without rounding it to float64.
+// whole is false if fractional part was cut, ok is false if value is not finite number
+func exactInteger(val interface{}) (n *big.Int, whole bool, ok bool) {
+	switch v := val.(type) {
+	case int64:
+		return big.NewInt(v), true, true
+	case uint64:
+		return new(big.Int).SetUint64(v), true, true
+	case int:
+		return big.NewInt(int64(v)), true, true
+	}
+	f, ok := numberValue(val)
+	if !ok || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, false, false
+	}
+	n, _ = big.NewFloat(math.Trunc(f)).Int(nil)
+	return n, f == math.Trunc(f), true
+}
+
+// appendPath makes new path not sharing memory with path given
+func appendPath(path []string, key string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, key)
+}
@@ -0,0 +1,287 @@
+package configuration
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindTestBase struct {
+	Name string `config:"name"`
+}
+
+type bindTestServer struct {
+	Host string `config:"host"`
+	Port int    `config:"port"`
+}
+
+type bindTestConfig struct {
+	bindTestBase
+	*BindTestExtra
+	Server   bindTestServer            `config:"server"`
+	Backup   *bindTestServer           `config:"backup"`
+	Servers  []bindTestServer          `config:"servers"`
+	Hosts    []string                  `config:"hosts"`
+	Pair     [2]int                    `config:"pair"`
+	Limits   map[string]uint8          `config:"limits"`
+	Timeout  time.Duration             `config:"timeout"`
+	Started  time.Time                 `config:"started"`
+	Ratio    float32                   `config:"ratio"`
+	Any      interface{}               `config:"any"`
+	Skipped  string                    `config:"-"`
+	Debug    bool                      // bound by field name
+	Nested   map[string]bindTestServer `config:"nested"`
+	internal string
+}
+
+// BindTestExtra is exported so pointer to it can be allocated by Unmarshal
+type BindTestExtra struct {
+	Extra string `config:"extra"`
+}
+
+func TestHJSONConfig_Unmarshal(t *testing.T) {
+	fl := &HJSONConfig{
+		filename: "",
+		hjsonMap: map[string]interface{}{
+			"name":   "svc",
+			"extra":  "more",
+			"server": map[string]interface{}{"host": "localhost", "port": float64(8080)},
+			"backup": map[string]interface{}{"host": "backup", "port": float64(8081)},
+			"servers": []interface{}{
+				map[string]interface{}{"host": "a", "port": float64(1)},
+				map[string]interface{}{"host": "b", "port": float64(2)},
+			},
+			"hosts":   []interface{}{"a", "b"},
+			"pair":    []interface{}{float64(1), float64(2)},
+			"limits":  map[string]interface{}{"a": float64(1), "b": float64(255)},
+			"timeout": "30s",
+			"started": "2026-01-01T00:00:00Z",
+			"ratio":   float64(0.5),
+			"any":     []interface{}{"x"},
+			"Skipped": "no",
+			"debug":   true,
+			"nested":  map[string]interface{}{"x": map[string]interface{}{"host": "x"}},
+		},
+	}
+	got := bindTestConfig{Skipped: "kept"}
+	if err := fl.Unmarshal(&got); err != nil {
+		t.Errorf("HJSONConfig.Unmarshal() error = %v", err)
+		return
+	}
+	want := bindTestConfig{
+		bindTestBase:  bindTestBase{Name: "svc"},
+		BindTestExtra: &BindTestExtra{Extra: "more"},
+		Server:        bindTestServer{Host: "localhost", Port: 8080},
+		Backup:        &bindTestServer{Host: "backup", Port: 8081},
+		Servers:       []bindTestServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+		Hosts:         []string{"a", "b"},
+		Pair:          [2]int{1, 2},
+		Limits:        map[string]uint8{"a": 1, "b": 255},
+		Timeout:       30 * time.Second,
+		Started:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Ratio:         0.5,
+		Any:           []interface{}{"x"},
+		Skipped:       "kept",
+		Debug:         true,
+		Nested:        map[string]bindTestServer{"x": {Host: "x"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HJSONConfig.Unmarshal() = %+v, want %+v", got, want)
+	}
+	var server bindTestServer
+	if err := fl.Unmarshal(&server, "server"); err != nil || server.Port != 8080 {
+		t.Errorf("HJSONConfig.Unmarshal() subtree = %+v, %v", server, err)
+	}
+}
+
+func TestHJSONConfig_Unmarshal_errors(t *testing.T) {
+	type target struct {
+		Port    int              `config:"port"`
+		Small   int8             `config:"small"`
+		Count   uint             `config:"count"`
+		Frac    int              `config:"frac"`
+		Name    string           `config:"name"`
+		Servers []bindTestServer `config:"servers"`
+		Timeout time.Duration    `config:"timeout"`
+		Keys    map[int]string   `config:"keys"`
+	}
+	type teststruct struct {
+		name        string
+		hjsonMap    map[string]interface{}
+		target      interface{}
+		path        []string
+		wantErrType string
+		wantErrs    []string
+	}
+	tests := []teststruct{
+		{
+			name:        "not initialized config",
+			hjsonMap:    nil,
+			target:      &target{},
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "not pointer target",
+			hjsonMap:    map[string]interface{}{},
+			target:      target{},
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "not existing path",
+			hjsonMap:    map[string]interface{}{},
+			target:      &target{},
+			path:        []string{"nothing"},
+			wantErrType: "*configuration.ConfigItemNotFound",
+		},
+		{
+			name: "all field problems are reported with full path",
+			hjsonMap: map[string]interface{}{
+				"section": map[string]interface{}{
+					"port":  "8080",
+					"small": float64(300),
+					"count": float64(-1),
+					"frac":  float64(1.5),
+					"name":  float64(1),
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a", "port": float64(1)},
+						map[string]interface{}{"host": true, "port": "x"},
+					},
+					"timeout": "30 parsecs",
+					"keys":    map[string]interface{}{},
+				},
+			},
+			target:      &target{},
+			path:        []string{"section"},
+			wantErrType: "*configuration.ConfigBindError",
+			wantErrs: []string{
+				"section.port: ",
+				"section.small: ",
+				"section.count: ",
+				"section.frac: ",
+				"section.name: ",
+				"section.servers.1.host: ",
+				"section.servers.1.port: ",
+				"section.timeout: ",
+				"section.keys: ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &HJSONConfig{filename: "", hjsonMap: tt.hjsonMap}
+			err := fl.Unmarshal(tt.target, tt.path...)
+			if err == nil {
+				t.Errorf("HJSONConfig.Unmarshal() error = nil, want error")
+				return
+			}
+			if tt.wantErrType != reflect.TypeOf(err).String() {
+				t.Errorf("HJSONConfig.Unmarshal() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if nil == tt.wantErrs {
+				return
+			}
+			errs := err.(*ConfigBindError).Errors()
+			if len(errs) != len(tt.wantErrs) {
+				t.Errorf("HJSONConfig.Unmarshal() errors = %v, want %d errors", errs, len(tt.wantErrs))
+				return
+			}
+			for i, e := range errs {
+				if reflect.TypeOf(e).String() != "*configuration.ConfigTypeMismatchError" {
+					t.Errorf("field error type = %v, want ConfigTypeMismatchError", reflect.TypeOf(e))
+				}
+				if !strings.HasPrefix(e.Error(), tt.wantErrs[i]) {
+					t.Errorf("field error = %v, want prefix %v", e, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_Unmarshal_bigIntegers(t *testing.T) {
+	var target struct {
+		Exact int64  `config:"exact"`
+		Max   uint64 `config:"max"`
+	}
+	fl, err := NewYAMLConfig([]byte("exact: 9007199254740993\nmax: 18446744073709551615\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = fl.Unmarshal(&target); err != nil {
+		t.Fatalf("HJSONConfig.Unmarshal() error = %v", err)
+	}
+	if target.Exact != 1<<53+1 || target.Max != 1<<64-1 {
+		t.Errorf("HJSONConfig.Unmarshal() = %+v, want exact integers", target)
+	}
+}
+
+func TestHJSONConfig_Unmarshal_defaults(t *testing.T) {
+	type db struct {
+		Host string `config:"host" default:"localhost"`
+		Port int    `config:"port" default:"5432"`
+		User string `config:"user" required:"true"`
+	}
+	type target struct {
+		Port    int           `config:"port" default:"8080"`
+		Timeout time.Duration `config:"timeout" default:"30s"`
+		Debug   bool          `config:"debug" default:"true"`
+		Ratio   *float64      `config:"ratio" default:"0.5"`
+		Hosts   []string      `config:"hosts" default:"a, b"`
+		Name    string        `config:"name" default:"unused"`
+		Key     string        `config:"key" required:"true"`
+		DB      db            `config:"db"`
+		Backup  *db           `config:"backup"`
+	}
+	fl := &HJSONConfig{filename: "", hjsonMap: map[string]interface{}{
+		"name": "given",
+		"key":  "secret",
+		"db":   map[string]interface{}{"user": "admin"},
+	}}
+	var got target
+	if err := fl.Unmarshal(&got); err != nil {
+		t.Errorf("HJSONConfig.Unmarshal() error = %v", err)
+		return
+	}
+	ratio := 0.5
+	want := target{
+		Port:    8080,
+		Timeout: 30 * time.Second,
+		Debug:   true,
+		Ratio:   &ratio,
+		Hosts:   []string{"a", "b"},
+		Name:    "given",
+		Key:     "secret",
+		DB:      db{Host: "localhost", Port: 5432, User: "admin"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HJSONConfig.Unmarshal() = %+v, want %+v", got, want)
+	}
+
+	type badDefaults struct {
+		Port  int            `config:"port" default:"eighty"`
+		Debug bool           `config:"debug" default:"maybe"`
+		Map   map[string]int `config:"map" default:"a=1"`
+	}
+	fl = &HJSONConfig{filename: "", hjsonMap: map[string]interface{}{"section": map[string]interface{}{}}}
+	err := fl.Unmarshal(&target{})
+	if err == nil {
+		t.Errorf("HJSONConfig.Unmarshal() without required values error = nil")
+		return
+	}
+	errs := err.(*ConfigBindError).Errors()
+	wantErrs := []string{"key: ", "db.user: "}
+	if len(errs) != len(wantErrs) {
+		t.Errorf("HJSONConfig.Unmarshal() errors = %v, want %v", errs, wantErrs)
+		return
+	}
+	for i, e := range errs {
+		if reflect.TypeOf(e).String() != "*configuration.ConfigItemNotFound" || !strings.HasPrefix(e.Error(), wantErrs[i]) {
+			t.Errorf("required field error = %v(%v), want ConfigItemNotFound with prefix %v", e, reflect.TypeOf(e), wantErrs[i])
+		}
+	}
+	err = fl.Unmarshal(&badDefaults{}, "section")
+	if err == nil || len(err.(*ConfigBindError).Errors()) != 3 {
+		t.Errorf("HJSONConfig.Unmarshal() with wrong defaults error = %v, want 3 field errors", err)
+	}
+}
@@ -0,0 +1,258 @@
+package configuration
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// Getter variants with defaults and panics. They are functions taking IConfig,
+// so own IConfig implementations get them for free.
+// GetXxxOr(c, def, path...) returns def if value is absent(ConfigItemNotFound) only,
+// other errors like ConfigTypeMismatchError are still returned so broken values are not hidden.
+// MustGetXxx(c, path...) panics if value can not be got, it's intended for startup code
+
+// isAbsent says if getter error means value is absent and default may be used
+func isAbsent(err error) bool {
+	_, ok := err.(*ConfigItemNotFound)
+	return ok
+}
+
+// mustValue panics if getter failed
+func mustValue(getter string, path []string, err error) {
+	if nil != err {
+		panic(fmt.Sprintf("configuration: %s(%s): %v", getter, pathString(path), err))
+	}
+}
+
+// valueOr calls getter and gives def if value is absent
+func valueOr[T any](get func(path ...string) (T, error), def T, path []string) (T, error) {
+	v, err := get(path...)
+	if isAbsent(err) {
+		return def, nil
+	}
+	return v, err
+}
+
+// mustGet calls getter and panics with getter name and path if it failed
+func mustGet[T any](getter string, get func(path ...string) (T, error), path []string) T {
+	v, err := get(path...)
+	mustValue(getter, path, err)
+	return v
+}
+
+// GetIntValueOr returns GetIntValue value or def if value is absent
+func GetIntValueOr(c IConfig, def int, path ...string) (int, error) {
+	return valueOr(c.GetIntValue, def, path)
+}
+
+// MustGetIntValue returns GetIntValue value or panics
+func MustGetIntValue(c IConfig, path ...string) int {
+	return mustGet("MustGetIntValue", c.GetIntValue, path)
+}
+
+// GetInt64ValueOr returns GetInt64Value value or def if value is absent
+func GetInt64ValueOr(c IConfig, def int64, path ...string) (int64, error) {
+	return valueOr(c.GetInt64Value, def, path)
+}
+
+// MustGetInt64Value returns GetInt64Value value or panics
+func MustGetInt64Value(c IConfig, path ...string) int64 {
+	return mustGet("MustGetInt64Value", c.GetInt64Value, path)
+}
+
+// GetUint64ValueOr returns GetUint64Value value or def if value is absent
+func GetUint64ValueOr(c IConfig, def uint64, path ...string) (uint64, error) {
+	return valueOr(c.GetUint64Value, def, path)
+}
+
+// MustGetUint64Value returns GetUint64Value value or panics
+func MustGetUint64Value(c IConfig, path ...string) uint64 {
+	return mustGet("MustGetUint64Value", c.GetUint64Value, path)
+}
+
+// GetFloatValueOr returns GetFloatValue value or def if value is absent
+func GetFloatValueOr(c IConfig, def float64, path ...string) (float64, error) {
+	return valueOr(c.GetFloatValue, def, path)
+}
+
+// MustGetFloatValue returns GetFloatValue value or panics
+func MustGetFloatValue(c IConfig, path ...string) float64 {
+	return mustGet("MustGetFloatValue", c.GetFloatValue, path)
+}
+
+// GetStringValueOr returns GetStringValue value or def if value is absent
+func GetStringValueOr(c IConfig, def string, path ...string) (string, error) {
+	return valueOr(c.GetStringValue, def, path)
+}
+
+// MustGetStringValue returns GetStringValue value or panics
+func MustGetStringValue(c IConfig, path ...string) string {
+	return mustGet("MustGetStringValue", c.GetStringValue, path)
+}
+
+// GetBooleanValueOr returns GetBooleanValue value or def if value is absent
+func GetBooleanValueOr(c IConfig, def bool, path ...string) (bool, error) {
+	return valueOr(c.GetBooleanValue, def, path)
+}
+
+// MustGetBooleanValue returns GetBooleanValue value or panics
+func MustGetBooleanValue(c IConfig, path ...string) bool {
+	return mustGet("MustGetBooleanValue", c.GetBooleanValue, path)
+}
+
+// GetDurationValueOr returns GetDurationValue value or def if value is absent
+func GetDurationValueOr(c IConfig, def time.Duration, path ...string) (time.Duration, error) {
+	return valueOr(c.GetDurationValue, def, path)
+}
+
+// MustGetDurationValue returns GetDurationValue value or panics
+func MustGetDurationValue(c IConfig, path ...string) time.Duration {
+	return mustGet("MustGetDurationValue", c.GetDurationValue, path)
+}
+
+// GetByteSizeValueOr returns GetByteSizeValue value or def if value is absent
+func GetByteSizeValueOr(c IConfig, def uint64, path ...string) (uint64, error) {
+	return valueOr(c.GetByteSizeValue, def, path)
+}
+
+// MustGetByteSizeValue returns GetByteSizeValue value or panics
+func MustGetByteSizeValue(c IConfig, path ...string) uint64 {
+	return mustGet("MustGetByteSizeValue", c.GetByteSizeValue, path)
+}
+
+// GetTimeValueOr returns GetTimeValue value or def if value is absent
+func GetTimeValueOr(c IConfig, def time.Time, path ...string) (time.Time, error) {
+	return valueOr(c.GetTimeValue, def, path)
+}
+
+// MustGetTimeValue returns GetTimeValue value or panics
+func MustGetTimeValue(c IConfig, path ...string) time.Time {
+	return mustGet("MustGetTimeValue", c.GetTimeValue, path)
+}
+
+// GetStringSliceOr returns GetStringSlice value or def if value is absent
+func GetStringSliceOr(c IConfig, def []string, path ...string) ([]string, error) {
+	return valueOr(c.GetStringSlice, def, path)
+}
+
+// MustGetStringSlice returns GetStringSlice value or panics
+func MustGetStringSlice(c IConfig, path ...string) []string {
+	return mustGet("MustGetStringSlice", c.GetStringSlice, path)
+}
+
+// GetIntSliceOr returns GetIntSlice value or def if value is absent
+func GetIntSliceOr(c IConfig, def []int, path ...string) ([]int, error) {
+	return valueOr(c.GetIntSlice, def, path)
+}
+
+// MustGetIntSlice returns GetIntSlice value or panics
+func MustGetIntSlice(c IConfig, path ...string) []int {
+	return mustGet("MustGetIntSlice", c.GetIntSlice, path)
+}
+
+// GetFloatSliceOr returns GetFloatSlice value or def if value is absent
+func GetFloatSliceOr(c IConfig, def []float64, path ...string) ([]float64, error) {
+	return valueOr(c.GetFloatSlice, def, path)
+}
+
+// MustGetFloatSlice returns GetFloatSlice value or panics
+func MustGetFloatSlice(c IConfig, path ...string) []float64 {
+	return mustGet("MustGetFloatSlice", c.GetFloatSlice, path)
+}
+
+// GetBoolSliceOr returns GetBoolSlice value or def if value is absent
+func GetBoolSliceOr(c IConfig, def []bool, path ...string) ([]bool, error) {
+	return valueOr(c.GetBoolSlice, def, path)
+}
+
+// MustGetBoolSlice returns GetBoolSlice value or panics
+func MustGetBoolSlice(c IConfig, path ...string) []bool {
+	return mustGet("MustGetBoolSlice", c.GetBoolSlice, path)
+}
+
+// GetStringMapOr returns GetStringMap value or def if value is absent
+func GetStringMapOr(c IConfig, def map[string]interface{}, path ...string) (map[string]interface{}, error) {
+	return valueOr(c.GetStringMap, def, path)
+}
+
+// MustGetStringMap returns GetStringMap value or panics
+func MustGetStringMap(c IConfig, path ...string) map[string]interface{} {
+	return mustGet("MustGetStringMap", c.GetStringMap, path)
+}
+
+// GetStringMapStringOr returns GetStringMapString value or def if value is absent
+func GetStringMapStringOr(c IConfig, def map[string]string, path ...string) (map[string]string, error) {
+	return valueOr(c.GetStringMapString, def, path)
+}
+
+// MustGetStringMapString returns GetStringMapString value or panics
+func MustGetStringMapString(c IConfig, path ...string) map[string]string {
+	return mustGet("MustGetStringMapString", c.GetStringMapString, path)
+}
+
+// Network getters are not in IConfig, so their variants take any value having the getter,
+// e.g. *HJSONConfig or configs built on it
+
+// GetURLValueOr returns GetURLValue value or def if value is absent
+func GetURLValueOr(c interface {
+	GetURLValue(path ...string) (*url.URL, error)
+}, def *url.URL, path ...string) (*url.URL, error) {
+	return valueOr(c.GetURLValue, def, path)
+}
+
+// MustGetURLValue returns GetURLValue value or panics
+func MustGetURLValue(c interface {
+	GetURLValue(path ...string) (*url.URL, error)
+}, path ...string) *url.URL {
+	return mustGet("MustGetURLValue", c.GetURLValue, path)
+}
+
+// GetIPValueOr returns GetIPValue value or def if value is absent
+func GetIPValueOr(c interface {
+	GetIPValue(path ...string) (net.IP, error)
+}, def net.IP, path ...string) (net.IP, error) {
+	return valueOr(c.GetIPValue, def, path)
+}
+
+// MustGetIPValue returns GetIPValue value or panics
+func MustGetIPValue(c interface {
+	GetIPValue(path ...string) (net.IP, error)
+}, path ...string) net.IP {
+	return mustGet("MustGetIPValue", c.GetIPValue, path)
+}
+
+// GetCIDRValueOr returns GetCIDRValue value or def if value is absent
+func GetCIDRValueOr(c interface {
+	GetCIDRValue(path ...string) (*net.IPNet, error)
+}, def *net.IPNet, path ...string) (*net.IPNet, error) {
+	return valueOr(c.GetCIDRValue, def, path)
+}
+
+// MustGetCIDRValue returns GetCIDRValue value or panics
+func MustGetCIDRValue(c interface {
+	GetCIDRValue(path ...string) (*net.IPNet, error)
+}, path ...string) *net.IPNet {
+	return mustGet("MustGetCIDRValue", c.GetCIDRValue, path)
+}
+
+// GetHostPortValueOr returns GetHostPortValue value or defHost and defPort if value is absent
+func GetHostPortValueOr(c interface {
+	GetHostPortValue(path ...string) (string, int, error)
+}, defHost string, defPort int, path ...string) (string, int, error) {
+	host, port, err := c.GetHostPortValue(path...)
+	if isAbsent(err) {
+		return defHost, defPort, nil
+	}
+	return host, port, err
+}
+
+// MustGetHostPortValue returns GetHostPortValue value or panics
+func MustGetHostPortValue(c interface {
+	GetHostPortValue(path ...string) (string, int, error)
+}, path ...string) (string, int) {
+	host, port, err := c.GetHostPortValue(path...)
+	mustValue("MustGetHostPortValue", path, err)
+	return host, port
+}
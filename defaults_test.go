@@ -0,0 +1,240 @@
+package configuration
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHJSONConfig_OrGetters(t *testing.T) {
+	var c IConfig = &HJSONConfig{hjsonMap: map[string]interface{}{
+		"section": map[string]interface{}{
+			"port":    8080.0,
+			"name":    "web",
+			"enabled": true,
+			"timeout": "5s",
+			"size":    "1KiB",
+			"cutoff":  "2026-01-01T00:00:00Z",
+			"hosts":   []interface{}{"a", "b"},
+			"labels":  map[string]interface{}{"app": "web"},
+		},
+	}}
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	type teststruct struct {
+		name        string
+		get         func(path ...string) (interface{}, error)
+		key         string
+		def         interface{}
+		want        interface{}
+		wrongKey    string
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name: "GetIntValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetIntValueOr(c, 10, p...) },
+			key:  "port", def: 10, want: 8080, wrongKey: "name",
+		},
+		{
+			name: "GetInt64ValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetInt64ValueOr(c, 10, p...) },
+			key:  "port", def: int64(10), want: int64(8080), wrongKey: "name",
+		},
+		{
+			name: "GetUint64ValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetUint64ValueOr(c, 10, p...) },
+			key:  "port", def: uint64(10), want: uint64(8080), wrongKey: "name",
+		},
+		{
+			name: "GetFloatValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetFloatValueOr(c, 0.5, p...) },
+			key:  "port", def: 0.5, want: 8080.0, wrongKey: "name",
+		},
+		{
+			name: "GetStringValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetStringValueOr(c, "default", p...) },
+			key:  "name", def: "default", want: "web", wrongKey: "port",
+		},
+		{
+			name: "GetBooleanValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetBooleanValueOr(c, true, p...) },
+			key:  "enabled", def: true, want: true, wrongKey: "port",
+		},
+		{
+			name: "GetDurationValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetDurationValueOr(c, time.Minute, p...) },
+			key:  "timeout", def: time.Minute, want: 5 * time.Second, wrongKey: "name",
+		},
+		{
+			name: "GetByteSizeValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetByteSizeValueOr(c, 1, p...) },
+			key:  "size", def: uint64(1), want: uint64(1024), wrongKey: "name",
+		},
+		{
+			name: "GetTimeValueOr",
+			get:  func(p ...string) (interface{}, error) { return GetTimeValueOr(c, time.Time{}, p...) },
+			key:  "cutoff", def: time.Time{}, want: cutoff, wrongKey: "name",
+		},
+		{
+			name: "GetStringSliceOr",
+			get:  func(p ...string) (interface{}, error) { return GetStringSliceOr(c, []string{"x"}, p...) },
+			key:  "hosts", def: []string{"x"}, want: []string{"a", "b"}, wrongKey: "port",
+		},
+		{
+			name: "GetIntSliceOr",
+			get:  func(p ...string) (interface{}, error) { return GetIntSliceOr(c, []int{1}, p...) },
+			key:  "port", def: []int{1}, wrongKey: "hosts",
+		},
+		{
+			name: "GetFloatSliceOr",
+			get:  func(p ...string) (interface{}, error) { return GetFloatSliceOr(c, []float64{1}, p...) },
+			key:  "port", def: []float64{1}, wrongKey: "hosts",
+		},
+		{
+			name: "GetBoolSliceOr",
+			get:  func(p ...string) (interface{}, error) { return GetBoolSliceOr(c, []bool{true}, p...) },
+			key:  "port", def: []bool{true}, wrongKey: "hosts",
+		},
+		{
+			name: "GetStringMapOr",
+			get:  func(p ...string) (interface{}, error) { return GetStringMapOr(c, map[string]interface{}{}, p...) },
+			key:  "labels", def: map[string]interface{}{}, want: map[string]interface{}{"app": "web"}, wrongKey: "name",
+		},
+		{
+			name: "GetStringMapStringOr",
+			get:  func(p ...string) (interface{}, error) { return GetStringMapStringOr(c, map[string]string{}, p...) },
+			key:  "labels", def: map[string]string{}, want: map[string]string{"app": "web"}, wrongKey: "name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if nil != tt.want {
+				got, err := tt.get("section", tt.key)
+				if err != nil || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s() = %v, %v, want %v", tt.name, got, err, tt.want)
+				}
+			}
+			// absent value and absent section give default
+			for _, p := range [][]string{{"section", "nothing"}, {"nothing", tt.key}} {
+				got, err := tt.get(p...)
+				if err != nil || !reflect.DeepEqual(got, tt.def) {
+					t.Errorf("%s(%v) = %v, %v, want default %v", tt.name, p, got, err, tt.def)
+				}
+			}
+			// wrong type is not hidden by default
+			got, err := tt.get("section", tt.wrongKey)
+			if nil == err || "*configuration.ConfigTypeMismatchError" != reflect.TypeOf(err).String() {
+				t.Errorf("%s() of wrong type = %v, %v, want ConfigTypeMismatchError", tt.name, got, err)
+			}
+			// path through scalar is not absent value too
+			if _, err = tt.get("section", "name", "deeper"); nil == err || "*configuration.ConfigTypeMismatchError" != reflect.TypeOf(err).String() {
+				t.Errorf("%s() through scalar error = %v, want ConfigTypeMismatchError", tt.name, err)
+			}
+		})
+	}
+	if _, err := GetIntValueOr(&HJSONConfig{}, 1, "port"); nil == err || "*configuration.ConfigUsageError" != reflect.TypeOf(err).String() {
+		t.Errorf("GetIntValueOr() of not initialized config error = %v, want ConfigUsageError", err)
+	}
+}
+
+func TestHJSONConfig_OrGetters_network(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{"listen": ":8080", "bad": "x"}}
+	defURL, _ := url.Parse("http://localhost")
+	if u, err := GetURLValueOr(fl, defURL, "upstream"); err != nil || u != defURL {
+		t.Errorf("GetURLValueOr() = %v, %v, want default", u, err)
+	}
+	if _, err := GetURLValueOr(fl, defURL, "bad"); nil == err {
+		t.Errorf("GetURLValueOr() of wrong value error = nil")
+	}
+	if ip, err := GetIPValueOr(fl, net.IPv4(127, 0, 0, 1), "ip"); err != nil || !ip.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("GetIPValueOr() = %v, %v, want default", ip, err)
+	}
+	if n, err := GetCIDRValueOr(fl, nil, "bad"); nil == err || nil != n {
+		t.Errorf("GetCIDRValueOr() of wrong value = %v, %v, want error", n, err)
+	}
+	if host, port, err := GetHostPortValueOr(fl, "localhost", 80, "listen"); err != nil || host != "" || port != 8080 {
+		t.Errorf("GetHostPortValueOr() = %v, %v, %v, want :8080", host, port, err)
+	}
+	if host, port, err := GetHostPortValueOr(fl, "localhost", 80, "nothing"); err != nil || host != "localhost" || port != 80 {
+		t.Errorf("GetHostPortValueOr() = %v, %v, %v, want default", host, port, err)
+	}
+}
+
+// mustPanic gives panic message of f or empty string
+func mustPanic(f func()) (msg string) {
+	defer func() {
+		if r := recover(); nil != r {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	f()
+	return ""
+}
+
+func TestHJSONConfig_MustGetters(t *testing.T) {
+	var c IConfig = &HJSONConfig{hjsonMap: map[string]interface{}{
+		"http": map[string]interface{}{
+			"port":   8080.0,
+			"listen": ":8080",
+			"hosts":  "a,b",
+		},
+	}}
+	if v := MustGetIntValue(c, "http", "port"); v != 8080 {
+		t.Errorf("MustGetIntValue() = %v, want 8080", v)
+	}
+	if v := MustGetStringSlice(c, "http", "hosts"); !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Errorf("MustGetStringSlice() = %v, want [a b]", v)
+	}
+	if host, port := MustGetHostPortValue(c.(*HJSONConfig), "http", "listen"); host != "" || port != 8080 {
+		t.Errorf("MustGetHostPortValue() = %v, %v, want :8080", host, port)
+	}
+	type teststruct struct {
+		name string
+		f    func()
+		want []string
+	}
+	tests := []teststruct{
+		{
+			name: "absent",
+			f:    func() { MustGetIntValue(c, "http", "timeout") },
+			want: []string{"MustGetIntValue(http.timeout)", "Item not found"},
+		},
+		{
+			name: "wrong type",
+			f:    func() { MustGetDurationValue(c, "http", "hosts") },
+			want: []string{"MustGetDurationValue(http.hosts)", "http.hosts: "},
+		},
+		{
+			name: "wrong item",
+			f:    func() { MustGetIntSlice(c, "http", "hosts") },
+			want: []string{"MustGetIntSlice(http.hosts)", "http.hosts.0: expected number"},
+		},
+		{
+			name: "network getter",
+			f:    func() { MustGetIPValue(c.(*HJSONConfig), "http", "listen") },
+			want: []string{"MustGetIPValue(http.listen)", "invalid IP address"},
+		},
+		{
+			name: "snapshot",
+			f:    func() { MustGetBooleanValue(c.Snapshot(), "http", "port", "deeper") },
+			want: []string{"MustGetBooleanValue(http.port.deeper)", `Can not get "deeper"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := mustPanic(tt.f)
+			if "" == msg {
+				t.Fatalf("%s: no panic", tt.name)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(msg, w) {
+					t.Errorf("%s: panic message %q does not contain %q", tt.name, msg, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AutoFormat is format name which makes GetConfigInstance detect format itself
+// by file extension or, for files with unknown extension, by file contents
+const AutoFormat = "auto"
+
+// ContentSniffer says if contents look like some format
+type ContentSniffer func(cnt []byte) bool
+
+// formatsByExtension keeps formats by lower case file extension with leading dot
+// guarded by formatsMutex as all registry
+var formatsByExtension = map[string]*registeredFormat{}
+
+// RegisterFormatDetection makes registered format available for "auto" format
+// extensions are case insensitive file extensions with leading dot(".hjson").
+// sniffer is used for files with unknown extension and may be nil
+func RegisterFormatDetection(name string, sniffer ContentSniffer, extensions ...string) error {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	f, ok := formatsByName[strings.ToLower(name)]
+	if !ok {
+		return NewConfigUsageError("Format " + name + " is not registered")
+	}
+	for _, ext := range extensions {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") || len(ext) < 2 {
+			return NewConfigUsageError("Extension " + ext + " must start with dot")
+		}
+		if other, ok := formatsByExtension[ext]; ok && other != f {
+			return NewConfigUsageError("Extension " + ext + " is already registered for format " + other.name)
+		}
+	}
+	for _, ext := range extensions {
+		formatsByExtension[strings.ToLower(ext)] = f
+	}
+	if nil != sniffer {
+		f.sniffer = sniffer
+	}
+	return nil
+}
+
+// LoadFile loads config file detecting its format by extension or contents
+// it's the same as GetConfigInstance(nil, "auto", path)
+func LoadFile(path string) (config IConfig, err error) {
+	return detectAndLoad(path)
+}
+
+// detectAndLoad is "auto" format factory
+func detectAndLoad(settings ...interface{}) (config IConfig, err error) {
+	if len(settings) == 0 {
+		return nil, NewConfigUsageError("No arguments given to auto format")
+	}
+	var cnt []byte
+	switch v := settings[0].(type) {
+	case string:
+		if f := formatByExtension(filepath.Ext(v)); nil != f {
+			return f.factory(settings...)
+		}
+		cnt, err = ioutil.ReadFile(v)
+		if err != nil {
+			return nil, NewConfigUsageError("Error loading file occurred: " + err.Error())
+		}
+	case []byte:
+		cnt = v
+	default:
+		return nil, NewConfigUsageError("Auto format needs file name or []byte contents to detect format")
+	}
+	candidates := sniffFormats(cnt)
+	switch len(candidates) {
+	case 0:
+		return nil, NewConfigUsageError("Can not detect configuration format")
+	case 1:
+		return candidates[0].factory(settings...)
+	default:
+		names := make([]string, len(candidates))
+		for i, f := range candidates {
+			names[i] = f.name
+		}
+		return nil, NewConfigUsageError("Ambiguous configuration format, candidates are: " + strings.Join(names, ", "))
+	}
+}
+
+// formatByExtension finds format by file extension
+func formatByExtension(ext string) *registeredFormat {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	return formatsByExtension[strings.ToLower(ext)]
+}
+
+// sniffFormats returns formats which contents look like and are parsed by
+func sniffFormats(cnt []byte) (candidates []*registeredFormat) {
+	formatsMutex.RLock()
+	list := make([]*registeredFormat, len(formatsList))
+	copy(list, formatsList)
+	formatsMutex.RUnlock()
+	for _, f := range list {
+		if nil == f.sniffer || !f.sniffer(cnt) {
+			continue
+		}
+		if _, err := f.factory(cnt); err != nil {
+			continue
+		}
+		candidates = append(candidates, f)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].name < candidates[j].name })
+	return candidates
+}
+
+var (
+	// sniffSectionRe matches INI section and TOML table headers
+	sniffSectionRe = regexp.MustCompile(`^\[[^\[\]]+\]$`)
+	// sniffTableArrayRe matches TOML array of tables headers
+	sniffTableArrayRe = regexp.MustCompile(`^\[\[[^\[\]]+\]\]$`)
+	// sniffEqualsRe matches key = value lines
+	sniffEqualsRe = regexp.MustCompile(`^[^=:\s\[{]+(\s+[^=:\s]+)*\s*=`)
+	// sniffColonRe matches key: value lines
+	sniffColonRe = regexp.MustCompile(`^[^=:\s\[{]+\s*:(\s|$)`)
+	// sniffEmptyValueRe matches "key:" lines without value which open YAML nested blocks
+	sniffEmptyValueRe = regexp.MustCompile(`^[^=:\s\[{]+\s*:$`)
+	// sniffTOMLValueRe matches values TOML allows: strings, numbers, booleans, arrays, inline tables and dates
+	sniffTOMLValueRe = regexp.MustCompile(`^("|'|\[|\{|true$|false$|[+-]?(\d|inf$|nan$))`)
+)
+
+// sniffLines calls f for every not empty line which is not a comment
+// commentChars are chars comment lines start with
+// stops and returns false if f returns false
+func sniffLines(cnt []byte, commentChars string, f func(line string, indented bool) bool) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(cnt))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if "" == line || strings.ContainsAny(line[:1], commentChars) {
+			continue
+		}
+		if !f(line, raw[0] == ' ' || raw[0] == '\t') {
+			return false
+		}
+	}
+	return true
+}
+
+// sniffHJSON says contents look like HJSON or JSON object
+func sniffHJSON(cnt []byte) bool {
+	first := ""
+	sniffLines(cnt, "#", func(line string, indented bool) bool {
+		if strings.HasPrefix(line, "//") {
+			return true
+		}
+		first = line
+		return false
+	})
+	return strings.HasPrefix(first, "{")
+}
+
+// sniffYAML says contents look like YAML mapping
+func sniffYAML(cnt []byte) bool {
+	mappings := 0
+	ok := sniffLines(cnt, "#", func(line string, indented bool) bool {
+		switch {
+		case line == "---":
+			mappings++
+		case indented:
+			// nested content
+		case sniffColonRe.MatchString(line):
+			mappings++
+		default:
+			return false
+		}
+		return true
+	})
+	return ok && mappings > 0
+}
+
+// sniffTOML says contents look like TOML
+func sniffTOML(cnt []byte) bool {
+	items := 0
+	ok := sniffLines(cnt, "#", func(line string, indented bool) bool {
+		switch {
+		case sniffSectionRe.MatchString(line), sniffTableArrayRe.MatchString(line):
+		case sniffEqualsRe.MatchString(line):
+			value := strings.TrimSpace(line[strings.Index(line, "=")+1:])
+			if !sniffTOMLValueRe.MatchString(value) {
+				return false
+			}
+		case indented:
+			// multiline arrays and strings
+			return true
+		default:
+			return false
+		}
+		items++
+		return true
+	})
+	return ok && items > 0
+}
+
+// sniffINI says contents look like INI
+// keys before any section must be plain and use "=": dotted top level keys
+// favour .properties and "key: value" lines outside sections favour YAML
+func sniffINI(cnt []byte) bool {
+	items := 0
+	section := false
+	ok := sniffLines(cnt, ";#", func(line string, indented bool) bool {
+		switch {
+		case sniffSectionRe.MatchString(line):
+			section = true
+		case sniffEqualsRe.MatchString(line):
+			if !section && strings.Contains(line[:strings.Index(line, "=")], ".") {
+				return false
+			}
+		case section && sniffColonRe.MatchString(line) && !sniffEmptyValueRe.MatchString(line):
+		default:
+			return false
+		}
+		items++
+		return true
+	})
+	return ok && items > 0
+}
+
+// sniffProperties says contents look like Java .properties
+// only "key = value" lines are accepted: "key: value" is legal there too,
+// but such contents are much more likely YAML
+func sniffProperties(cnt []byte) bool {
+	items := 0
+	continued := false
+	ok := sniffLines(cnt, "#!", func(line string, indented bool) bool {
+		wasContinued := continued
+		continued = strings.HasSuffix(line, `\`)
+		switch {
+		case wasContinued:
+			return true
+		case indented, sniffSectionRe.MatchString(line), sniffTableArrayRe.MatchString(line):
+			return false
+		case !sniffEqualsRe.MatchString(line):
+			return false
+		}
+		items++
+		return true
+	})
+	return ok && items > 0
+}
@@ -0,0 +1,145 @@
+package configuration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configuration-detect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, cnt string) string {
+		p := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(p, []byte(cnt), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	type teststruct struct {
+		name        string
+		path        string
+		wantType    string
+		wantErr     bool
+		wantErrType string
+		wantErrText string
+	}
+	tests := []teststruct{
+		{name: "hjson by extension", path: "./test.hjson", wantType: "*configuration.HJSONConfig"},
+		{name: "yaml by extension", path: "./test.yaml", wantType: "*configuration.YAMLConfig"},
+		{name: "toml by extension", path: "./test.toml", wantType: "*configuration.TOMLConfig"},
+		{name: "ini by extension", path: "./test.ini", wantType: "*configuration.INIConfig"},
+		{name: "properties by extension", path: "./test.properties", wantType: "*configuration.PropertiesConfig"},
+		{name: "upper case extension", path: write("APP.YML", "a: 1\n"), wantType: "*configuration.YAMLConfig"},
+		{name: "sniffed hjson", path: write("app1", "# comment\n{\n  a: 1\n}\n"), wantType: "*configuration.HJSONConfig"},
+		{name: "sniffed yaml", path: write("app2", "---\na:\n  b: [1, 2]\nc: x\n"), wantType: "*configuration.YAMLConfig"},
+		{name: "sniffed toml", path: write("app3.conf", "[[servers]]\nhost = \"a\"\n"), wantType: "*configuration.TOMLConfig"},
+		{name: "sniffed ini", path: write("app4", "; comment\n[server]\nhost = localhost\n"), wantType: "*configuration.INIConfig"},
+		{name: "sniffed properties", path: write("app5", "! comment\ndb.host = localhost\n"), wantType: "*configuration.PropertiesConfig"},
+		{name: "sniffed flat yaml", path: write("app8", "name: web\nport: 8080\n"), wantType: "*configuration.YAMLConfig"},
+		{name: "sniffed nested yaml", path: write("app9", "server:\n  port: 8080\n"), wantType: "*configuration.YAMLConfig"},
+		{name: "sniffed plain properties", path: write("app10", "db.url=jdbc:x\n"), wantType: "*configuration.PropertiesConfig"},
+		{name: "sniffed ini with colons", path: write("app11", "[server]\nhost: localhost\n"), wantType: "*configuration.INIConfig"},
+		{
+			name:        "ambiguous contents",
+			path:        write("app6", "port = 8080\n"),
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+			wantErrText: "ini, properties, toml",
+		},
+		{
+			name:        "not detectable contents",
+			path:        write("app7", "just some text\n"),
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "not existing file",
+			path:        filepath.Join(dir, "not_existing"),
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadFile(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("LoadFile() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.wantErrText) {
+				t.Errorf("LoadFile() error = %v, want it contains %v", err, tt.wantErrText)
+				return
+			}
+			if !tt.wantErr && reflect.TypeOf(got).String() != tt.wantType {
+				t.Errorf("LoadFile() type = %v, want %v", reflect.TypeOf(got), tt.wantType)
+				return
+			}
+			if !tt.wantErr {
+				// detected config must be file backed
+				if err = got.ReloadInternalMap(); err != nil {
+					t.Errorf("ReloadInternalMap() after LoadFile() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetConfigInstance_auto(t *testing.T) {
+	c, err := GetConfigInstance(nil, "AUTO", []byte("{\"a\": 1}"))
+	if err != nil {
+		t.Errorf("GetConfigInstance() auto with bytes error = %v", err)
+	} else if reflect.TypeOf(c).String() != "*configuration.HJSONConfig" {
+		t.Errorf("GetConfigInstance() auto with bytes type = %v", reflect.TypeOf(c))
+	}
+	_, err = GetConfigInstance(nil, "auto", map[string]interface{}{"a": 1})
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+		t.Errorf("GetConfigInstance() auto with map error = %v, want ConfigUsageError", err)
+	}
+	_, err = GetConfigInstance(nil, "auto")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+		t.Errorf("GetConfigInstance() auto without arguments error = %v, want ConfigUsageError", err)
+	}
+}
+
+func TestRegisterFormatDetection(t *testing.T) {
+	err := RegisterFormatDetection("testformat_detect_not_registered", nil, ".tfd")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+		t.Errorf("RegisterFormatDetection() for not registered format error = %v, want ConfigUsageError", err)
+	}
+	err = RegisterFormat("testformat_detect", func(settings ...interface{}) (IConfig, error) {
+		return NewHJSONConfig(map[string]interface{}{"detected": true})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = RegisterFormatDetection("testformat_detect", nil, ".yml")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+		t.Errorf("RegisterFormatDetection() with taken extension error = %v, want ConfigUsageError", err)
+	}
+	err = RegisterFormatDetection("testformat_detect", nil, "tfd")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+		t.Errorf("RegisterFormatDetection() with extension without dot error = %v, want ConfigUsageError", err)
+	}
+	if err = RegisterFormatDetection("TestFormat_Detect", nil, ".TFD"); err != nil {
+		t.Errorf("RegisterFormatDetection() error = %v", err)
+	}
+	c, err := LoadFile("whatever.tfd")
+	if err != nil {
+		t.Errorf("LoadFile() error = %v", err)
+		return
+	}
+	if b, err := c.GetBooleanValue("detected"); err != nil || !b {
+		t.Errorf("LoadFile() did not use registered format factory")
+	}
+}
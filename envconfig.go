@@ -0,0 +1,143 @@
+package configuration
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultEnvSeparator separates path elements in environment variable names
+const DefaultEnvSeparator = "__"
+
+// EnvOverride describes config value overridden by environment variable
+type EnvOverride struct {
+	// Variable is environment variable name
+	Variable string
+	// Path is config path of value overridden
+	Path []string
+	// Value is value after type coercion
+	Value interface{}
+}
+
+// EnvConfig is overlay which overrides base config values by environment variables.
+// With prefix "APP_" and separator "__" variable APP_SECTION1__SUBSECTION2__VALUE
+// overrides GetValue("section1", "subsection2", "value").
+// Path elements match base config keys case insensitively and values are coerced
+// to type of value they override: numbers, booleans, strings or comma separated arrays
+type EnvConfig struct {
+	HJSONConfig
+	base      IConfig
+	prefix    string
+	separator string
+	overrides []EnvOverride
+}
+
+// NewEnvConfig makes environment overlay on base config
+// prefix must not be empty, empty separator means DefaultEnvSeparator
+func NewEnvConfig(base IConfig, prefix string, separator string) (fl *EnvConfig, err error) {
+	if nil == base {
+		return nil, NewConfigUsageError("Base config must not be nil")
+	}
+	if "" == prefix {
+		return nil, NewConfigUsageError("Environment variables prefix must not be empty")
+	}
+	if "" == separator {
+		separator = DefaultEnvSeparator
+	}
+	fl = &EnvConfig{base: base, prefix: prefix, separator: separator}
+	if err = fl.apply(); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+// apply builds internal map from base config and environment
+func (fl *EnvConfig) apply() error {
+	baseMap, err := configMap(fl.base)
+	if err != nil {
+		return err
+	}
+	m := deepCopyValue(baseMap).(map[string]interface{})
+	environ := os.Environ()
+	sort.Strings(environ)
+	overrides := []EnvOverride{}
+	for _, kv := range environ {
+		pos := strings.Index(kv, "=")
+		if pos < 0 {
+			continue
+		}
+		name, raw := kv[:pos], kv[pos+1:]
+		if !strings.HasPrefix(name, fl.prefix) || len(name) == len(fl.prefix) {
+			continue
+		}
+		path := strings.Split(name[len(fl.prefix):], fl.separator)
+		for _, p := range path {
+			if "" == p {
+				return NewConfigUsageError("Environment variable " + name + " has empty path element")
+			}
+		}
+		realPath, v, err := overrideValue(m, path, raw)
+		if err != nil {
+			return NewConfigTypeMismatchError("Environment variable " + name + ": " + err.Error())
+		}
+		overrides = append(overrides, EnvOverride{Variable: name, Path: realPath, Value: v})
+	}
+	fl.mutex.Lock()
+	fl.filename = ""
+	fl.hjsonMap = m
+	fl.overrides = overrides
+	fl.mutex.Unlock()
+	return nil
+}
+
+// SetDefaultLoadSetting sets load settings of base config and applies environment again
+func (fl *EnvConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	if err = fl.base.SetDefaultLoadSetting(sl...); err != nil {
+		return err
+	}
+	return fl.apply()
+}
+
+// CheckExternalConfig checks external configuration of base config
+func (fl *EnvConfig) CheckExternalConfig() (err error) {
+	return fl.base.CheckExternalConfig()
+}
+
+// ReloadInternalMap reloads base config and applies environment again
+func (fl *EnvConfig) ReloadInternalMap() (err error) {
+	if err = fl.base.ReloadInternalMap(); err != nil {
+		return err
+	}
+	return fl.apply()
+}
+
+// Overrides returns all values overridden by environment variables sorted by variable name
+func (fl *EnvConfig) Overrides() []EnvOverride {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	res := make([]EnvOverride, len(fl.overrides))
+	copy(res, fl.overrides)
+	return res
+}
+
+// IsOverridden says if value by path or some value inside it was overridden by environment
+func (fl *EnvConfig) IsOverridden(path ...string) bool {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	for _, o := range fl.overrides {
+		if len(o.Path) < len(path) {
+			continue
+		}
+		match := true
+		for i, p := range path {
+			if !strings.EqualFold(o.Path[i], p) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
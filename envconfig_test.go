@@ -0,0 +1,169 @@
+package configuration
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestNewEnvConfig(t *testing.T) {
+	base := func() IConfig {
+		c, _ := NewHJSONConfig(map[string]interface{}{
+			"section1": map[string]interface{}{
+				"subsection2": map[string]interface{}{
+					"value": float64(1),
+					"name":  "base",
+				},
+				"enabled": false,
+				"hosts":   []interface{}{"a"},
+				"ports":   []interface{}{float64(80)},
+			},
+			"CONFIG_FILE": "base.hjson",
+			"scalar":      "x",
+		})
+		return c
+	}
+	type teststruct struct {
+		name        string
+		env         map[string]string
+		wantErr     bool
+		wantErrType string
+		checker     func(fl *EnvConfig) bool
+	}
+	tests := []teststruct{
+		{
+			name: "values are overridden with coercion",
+			env: map[string]string{
+				"TESTENVCFG_SECTION1__SUBSECTION2__VALUE": "5",
+				"TESTENVCFG_SECTION1__ENABLED":            "true",
+				"TESTENVCFG_SECTION1__HOSTS":              "b, c",
+				"TESTENVCFG_SECTION1__PORTS":              "81,82",
+				"TESTENVCFG_CONFIG_FILE":                  "env.hjson",
+				"TESTENVCFG_NEW__KEY":                     "42",
+			},
+			checker: func(fl *EnvConfig) bool {
+				i, err := fl.GetIntValue("section1", "subsection2", "value")
+				if err != nil || i != 5 {
+					t.Errorf("EnvConfig.GetIntValue() = %v, %v, want 5", i, err)
+				}
+				b, err := fl.GetBooleanValue("section1", "enabled")
+				if err != nil || !b {
+					t.Errorf("EnvConfig.GetBooleanValue() = %v, %v, want true", b, err)
+				}
+				v, _ := fl.GetValue("section1", "hosts")
+				if !reflect.DeepEqual(v, []interface{}{"b", "c"}) {
+					t.Errorf("EnvConfig.GetValue() = %v, want [b c]", v)
+				}
+				v, _ = fl.GetValue("section1", "ports")
+				if !reflect.DeepEqual(v, []interface{}{float64(81), float64(82)}) {
+					t.Errorf("EnvConfig.GetValue() = %v, want [81 82]", v)
+				}
+				s, err := fl.GetStringValue("CONFIG_FILE")
+				if err != nil || s != "env.hjson" {
+					t.Errorf("EnvConfig.GetStringValue() = %v, %v, want env.hjson", s, err)
+				}
+				i, err = fl.GetIntValue("new", "key")
+				if err != nil || i != 42 {
+					t.Errorf("EnvConfig.GetIntValue() for new key = %v, %v, want 42", i, err)
+				}
+				s, err = fl.GetStringValue("section1", "subsection2", "name")
+				if err != nil || s != "base" {
+					t.Errorf("EnvConfig.GetStringValue() not overridden = %v, %v, want base", s, err)
+				}
+				if !fl.IsOverridden("section1", "subsection2", "value") || !fl.IsOverridden("section1") {
+					t.Errorf("EnvConfig.IsOverridden() = false for overridden value")
+				}
+				if fl.IsOverridden("section1", "subsection2", "name") || fl.IsOverridden("scalar") {
+					t.Errorf("EnvConfig.IsOverridden() = true for not overridden value")
+				}
+				o := fl.Overrides()
+				if len(o) != 6 || o[0].Variable != "TESTENVCFG_CONFIG_FILE" || !reflect.DeepEqual(o[0].Path, []string{"CONFIG_FILE"}) {
+					t.Errorf("EnvConfig.Overrides() = %v", o)
+				}
+				return true
+			},
+		},
+		{
+			name:        "number expected",
+			env:         map[string]string{"TESTENVCFG_SECTION1__SUBSECTION2__VALUE": "five"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name:        "object can not be overridden",
+			env:         map[string]string{"TESTENVCFG_SECTION1": "x"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name:        "scalar can not have nested keys",
+			env:         map[string]string{"TESTENVCFG_SCALAR__X": "x"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name:        "empty path element",
+			env:         map[string]string{"TESTENVCFG_SECTION1____X": "x"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			b := base()
+			fl, err := NewEnvConfig(b, "TESTENVCFG_", "")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewEnvConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("NewEnvConfig() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if nil != tt.checker {
+				tt.checker(fl)
+				// base config must stay untouched
+				i, _ := b.GetIntValue("section1", "subsection2", "value")
+				if i != 1 {
+					t.Errorf("base config was changed by EnvConfig")
+				}
+			}
+		})
+	}
+}
+
+func TestEnvConfig_ReloadInternalMap(t *testing.T) {
+	if _, err := NewEnvConfig(nil, "X_", ""); err == nil {
+		t.Errorf("NewEnvConfig() with nil base error = nil")
+	}
+	base, err := NewHJSONConfig("./test.hjson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = NewEnvConfig(base, "", ""); err == nil {
+		t.Errorf("NewEnvConfig() with empty prefix error = nil")
+	}
+	fl, err := NewEnvConfig(base, "TESTENVRELOAD.", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("TESTENVRELOAD.CONFIG_FILE", "changed")
+	defer os.Unsetenv("TESTENVRELOAD.CONFIG_FILE")
+	if fl.IsOverridden("CONFIG_FILE") {
+		t.Errorf("EnvConfig.IsOverridden() = true before reload")
+	}
+	if err = fl.CheckExternalConfig(); err != nil {
+		t.Errorf("EnvConfig.CheckExternalConfig() error = %v", err)
+	}
+	if err = fl.ReloadInternalMap(); err != nil {
+		t.Errorf("EnvConfig.ReloadInternalMap() error = %v", err)
+	}
+	s, err := fl.GetStringValue("CONFIG_FILE")
+	if err != nil || s != "changed" {
+		t.Errorf("EnvConfig.GetStringValue() after reload = %v, %v, want changed", s, err)
+	}
+}
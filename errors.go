@@ -1,5 +1,9 @@
 package configuration
 
+import (
+	"strings"
+)
+
 /*
 In this file we would store different error for sonfiguration implementation
 */
@@ -95,3 +99,58 @@ func NewConfigTypeMismatchError(s string) *ConfigTypeMismatchError {
 func (e *ConfigTypeMismatchError) Error() string {
 	return e.str
 }
+
+// ConfigParseError is intended for syntax errors found by format parsers of this package
+type ConfigParseError struct {
+	str string
+}
+
+// NewConfigParseError generates error object
+func NewConfigParseError(s string) *ConfigParseError {
+	return &ConfigParseError{str: s}
+}
+
+// Error is standard error interface h
+func (e *ConfigParseError) Error() string {
+	return e.str
+}
+
+// ConfigBindError is intended for struct binding errors. It collects problems of all fields
+type ConfigBindError struct {
+	str    string
+	errors []error
+}
+
+// NewConfigBindError generates error object from field level errors
+func NewConfigBindError(errs []error) *ConfigBindError {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return &ConfigBindError{str: strings.Join(msgs, "; "), errors: errs}
+}
+
+// Error is standard error interface h
+func (e *ConfigBindError) Error() string {
+	return e.str
+}
+
+// Errors returns all field level errors
+func (e *ConfigBindError) Errors() []error {
+	return e.errors
+}
+
+// ConfigReloadError is given by ReloadAll when some of tagged configs can not be reloaded
+type ConfigReloadError struct {
+	str string
+}
+
+// NewConfigReloadError generates error object
+func NewConfigReloadError(s string) *ConfigReloadError {
+	return &ConfigReloadError{str: s}
+}
+
+// Error is standard error interface h
+func (e *ConfigReloadError) Error() string {
+	return e.str
+}
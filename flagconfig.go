@@ -0,0 +1,256 @@
+package configuration
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FlagConfig is highest priority overlay which overrides base config values by command line flags.
+// Flag names are dotted config paths: --section1.subsection2.value=5 overrides
+// GetValue("section1", "subsection2", "value"). Values are coerced to type of value they override
+type FlagConfig struct {
+	HJSONConfig
+	base IConfig
+	// flags are raw flag values in the order they were given
+	flags []flagOverride
+	// args are arguments left after flags
+	args []string
+}
+
+// flagOverride is one flag given
+type flagOverride struct {
+	path []string
+	raw  string
+}
+
+// NewArgsConfig makes overlay on base config from os.Args style arguments(without program name):
+// --a.b=1, --a.b 1, -a.b=1. Flag without value is true for boolean values.
+// Parsing stops at first non flag argument or after "--"; rest arguments are available by Args().
+// Flags which do not correspond to any base config value are reported by ConfigItemNotFound error
+func NewArgsConfig(base IConfig, args []string) (fl *FlagConfig, err error) {
+	fl, err = newFlagConfig(base)
+	if err != nil {
+		return nil, err
+	}
+	baseMap := fl.getMap()
+	unknown := []string{}
+	i := 0
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if "--" == arg {
+			i++
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		raw := ""
+		hasValue := false
+		if pos := strings.Index(name, "="); pos >= 0 {
+			name, raw, hasValue = name[:pos], name[pos+1:], true
+		}
+		path := strings.Split(name, ".")
+		old, ok := lookupPath(baseMap, path)
+		if !ok {
+			unknown = append(unknown, "--"+name)
+			continue
+		}
+		if !hasValue {
+			if _, isBool := old.(bool); isBool {
+				raw = "true"
+			} else if i+1 < len(args) {
+				i++
+				raw = args[i]
+			} else {
+				return nil, NewConfigUsageError("Flag --" + name + " needs a value")
+			}
+		}
+		fl.flags = append(fl.flags, flagOverride{path: path, raw: raw})
+	}
+	fl.args = append([]string{}, args[i:]...)
+	if len(unknown) > 0 {
+		return nil, NewConfigItemNotFound("Unknown flags: " + strings.Join(unknown, ", "))
+	}
+	if err = fl.apply(); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+// BindFlags makes overlay on base config and registers flag in fs for every scalar
+// and array value of base config. Flag name is dotted path, default is current value.
+// Values set while fs.Parse() override base ones at once; wrong values are fs.Parse() errors.
+// Unknown flags are reported by flag package itself
+func BindFlags(fs *flag.FlagSet, base IConfig) (fl *FlagConfig, err error) {
+	if nil == fs {
+		return nil, NewConfigUsageError("Flag set must not be nil")
+	}
+	fl, err = newFlagConfig(base)
+	if err != nil {
+		return nil, err
+	}
+	fl.defineFlags(fs, fl.getMap(), nil)
+	return fl, nil
+}
+
+// newFlagConfig makes overlay with copy of base map
+func newFlagConfig(base IConfig) (fl *FlagConfig, err error) {
+	if nil == base {
+		return nil, NewConfigUsageError("Base config must not be nil")
+	}
+	fl = &FlagConfig{base: base}
+	if err = fl.apply(); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+// defineFlags registers flags for values of m recursively
+func (fl *FlagConfig) defineFlags(fs *flag.FlagSet, m map[string]interface{}, path []string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if "" == k || strings.ContainsAny(k, ".= \t") || strings.HasPrefix(k, "-") {
+			// such keys can not be flag path elements
+			continue
+		}
+		p := appendPath(path, k)
+		switch v := m[k].(type) {
+		case nil:
+		case map[string]interface{}:
+			fl.defineFlags(fs, v, p)
+		default:
+			_, isBool := v.(bool)
+			fs.Var(&configFlag{owner: fl, path: p, value: formatFlagValue(v), isBool: isBool}, strings.Join(p, "."), "config value "+pathString(p))
+		}
+	}
+}
+
+// formatFlagValue makes flag text from config value
+func formatFlagValue(v interface{}) string {
+	if l, ok := v.([]interface{}); ok {
+		items := make([]string, len(l))
+		for i, item := range l {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// apply builds internal map from base config and flags
+func (fl *FlagConfig) apply() error {
+	baseMap, err := configMap(fl.base)
+	if err != nil {
+		return err
+	}
+	m := deepCopyValue(baseMap).(map[string]interface{})
+	for _, f := range fl.flags {
+		if _, _, err = overrideValue(m, f.path, f.raw); err != nil {
+			return NewConfigTypeMismatchError("Flag --" + strings.Join(f.path, ".") + ": " + err.Error())
+		}
+	}
+	fl.setSource("", m)
+	return nil
+}
+
+// Args returns arguments left after flags by NewArgsConfig
+func (fl *FlagConfig) Args() []string {
+	return fl.args
+}
+
+// IsOverridden says if value by path or some value inside it was overridden by flag
+func (fl *FlagConfig) IsOverridden(path ...string) bool {
+	for _, f := range fl.flags {
+		if len(f.path) < len(path) {
+			continue
+		}
+		match := true
+		for i, p := range path {
+			if !strings.EqualFold(f.path[i], p) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDefaultLoadSetting sets load settings of base config and applies flags again
+func (fl *FlagConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	if err = fl.base.SetDefaultLoadSetting(sl...); err != nil {
+		return err
+	}
+	return fl.apply()
+}
+
+// CheckExternalConfig checks external configuration of base config
+func (fl *FlagConfig) CheckExternalConfig() (err error) {
+	return fl.base.CheckExternalConfig()
+}
+
+// ReloadInternalMap reloads base config and applies flags again
+func (fl *FlagConfig) ReloadInternalMap() (err error) {
+	if err = fl.base.ReloadInternalMap(); err != nil {
+		return err
+	}
+	return fl.apply()
+}
+
+// lookupPath finds value by path matching keys case insensitively
+func lookupPath(m map[string]interface{}, path []string) (interface{}, bool) {
+	var v interface{} = m
+	for _, name := range path {
+		current, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		key, ok := findKey(current, name)
+		if !ok {
+			return nil, false
+		}
+		v = current[key]
+	}
+	return v, true
+}
+
+// configFlag is flag.Value bound to config path
+type configFlag struct {
+	owner  *FlagConfig
+	path   []string
+	value  string
+	isBool bool
+}
+
+// String is flag.Value method
+func (f *configFlag) String() string {
+	if nil == f {
+		return ""
+	}
+	return f.value
+}
+
+// Set is flag.Value method. It overrides config value at once
+func (f *configFlag) Set(s string) error {
+	f.owner.flags = append(f.owner.flags, flagOverride{path: f.path, raw: s})
+	if err := f.owner.apply(); err != nil {
+		f.owner.flags = f.owner.flags[:len(f.owner.flags)-1]
+		return err
+	}
+	f.value = s
+	return nil
+}
+
+// IsBoolFlag makes boolean flags work without value
+func (f *configFlag) IsBoolFlag() bool {
+	return f.isBool
+}
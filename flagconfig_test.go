@@ -0,0 +1,155 @@
+package configuration
+
+import (
+	"flag"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func flagTestBase() IConfig {
+	c, _ := NewHJSONConfig(map[string]interface{}{
+		"section1": map[string]interface{}{
+			"subsection2": map[string]interface{}{
+				"value": float64(1),
+			},
+			"debug": false,
+			"hosts": []interface{}{"a"},
+			"name":  "base",
+		},
+		"with.dot": "x",
+	})
+	return c
+}
+
+func TestNewArgsConfig(t *testing.T) {
+	type teststruct struct {
+		name        string
+		args        []string
+		wantErr     bool
+		wantErrType string
+		checker     func(fl *FlagConfig) bool
+	}
+	tests := []teststruct{
+		{
+			name: "all flag forms",
+			args: []string{"--section1.subsection2.value=5", "-section1.debug", "--SECTION1.hosts", "b,c", "file1", "--section1.name=x"},
+			checker: func(fl *FlagConfig) bool {
+				i, err := fl.GetIntValue("section1", "subsection2", "value")
+				if err != nil || i != 5 {
+					t.Errorf("FlagConfig.GetIntValue() = %v, %v, want 5", i, err)
+				}
+				b, err := fl.GetBooleanValue("section1", "debug")
+				if err != nil || !b {
+					t.Errorf("FlagConfig.GetBooleanValue() = %v, %v, want true", b, err)
+				}
+				v, _ := fl.GetValue("section1", "hosts")
+				if !reflect.DeepEqual(v, []interface{}{"b", "c"}) {
+					t.Errorf("FlagConfig.GetValue() = %v, want [b c]", v)
+				}
+				s, _ := fl.GetStringValue("section1", "name")
+				if s != "base" {
+					t.Errorf("FlagConfig.GetStringValue() = %v, flags after arguments must not be parsed", s)
+				}
+				if !reflect.DeepEqual(fl.Args(), []string{"file1", "--section1.name=x"}) {
+					t.Errorf("FlagConfig.Args() = %v", fl.Args())
+				}
+				if !fl.IsOverridden("section1", "hosts") || fl.IsOverridden("section1", "name") {
+					t.Errorf("FlagConfig.IsOverridden() gives wrong results")
+				}
+				return true
+			},
+		},
+		{
+			name: "double dash stops flags",
+			args: []string{"--section1.debug=true", "--", "--section1.name=x"},
+			checker: func(fl *FlagConfig) bool {
+				if !reflect.DeepEqual(fl.Args(), []string{"--section1.name=x"}) {
+					t.Errorf("FlagConfig.Args() = %v", fl.Args())
+				}
+				return true
+			},
+		},
+		{
+			name:        "unknown flags",
+			args:        []string{"--section1.nothing=1", "--section1.subsection2.value=5", "--other"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigItemNotFound",
+		},
+		{
+			name:        "wrong value type",
+			args:        []string{"--section1.subsection2.value=five"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name:        "value is absent",
+			args:        []string{"--section1.name"},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl, err := NewArgsConfig(flagTestBase(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewArgsConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("NewArgsConfig() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if nil != tt.checker {
+				tt.checker(fl)
+			}
+		})
+	}
+}
+
+func TestBindFlags(t *testing.T) {
+	base := flagTestBase()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fl, err := BindFlags(fs, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"section1.subsection2.value", "section1.debug", "section1.hosts", "section1.name"} {
+		if nil == fs.Lookup(name) {
+			t.Errorf("BindFlags() did not register flag %v", name)
+		}
+	}
+	if nil != fs.Lookup("with.dot") {
+		t.Errorf("BindFlags() registered flag for key with dot")
+	}
+	if v := fs.Lookup("section1.hosts").DefValue; v != "a" {
+		t.Errorf("BindFlags() flag default = %v, want a", v)
+	}
+	if err = fs.Parse([]string{"-section1.subsection2.value=5", "-section1.debug", "rest"}); err != nil {
+		t.Fatal(err)
+	}
+	i, err := fl.GetIntValue("section1", "subsection2", "value")
+	if err != nil || i != 5 {
+		t.Errorf("FlagConfig.GetIntValue() = %v, %v, want 5", i, err)
+	}
+	b, err := fl.GetBooleanValue("section1", "debug")
+	if err != nil || !b {
+		t.Errorf("FlagConfig.GetBooleanValue() = %v, %v, want true", b, err)
+	}
+	if i, _ = base.GetIntValue("section1", "subsection2", "value"); i != 1 {
+		t.Errorf("base config was changed by FlagConfig")
+	}
+	if err = fs.Parse([]string{"-section1.subsection2.value=five"}); err == nil {
+		t.Errorf("FlagSet.Parse() with wrong value type error = nil")
+	}
+	if i, _ = fl.GetIntValue("section1", "subsection2", "value"); i != 5 {
+		t.Errorf("FlagConfig.GetIntValue() after wrong flag = %v, want 5", i)
+	}
+	if err = fs.Parse([]string{"-section1.unknown=1"}); err == nil {
+		t.Errorf("FlagSet.Parse() with unknown flag error = nil")
+	}
+	if _, err = BindFlags(nil, base); err == nil {
+		t.Errorf("BindFlags() with nil flag set error = nil")
+	}
+}
@@ -0,0 +1,133 @@
+package configuration
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatFactory creates config instance of some format
+// settings are all GetConfigInstance arguments after tag and format
+type FormatFactory func(settings ...interface{}) (IConfig, error)
+
+// registeredFormat is format registry entry
+type registeredFormat struct {
+	name    string
+	factory FormatFactory
+	// sniffer is used by "auto" format to detect format by file contents
+	sniffer ContentSniffer
+}
+
+var (
+	// formatsMutex guards formats registry
+	formatsMutex sync.RWMutex
+	// formatsByName keeps formats by lower case name and aliases
+	formatsByName = map[string]*registeredFormat{}
+	// formatsList keeps formats in registration order
+	formatsList []*registeredFormat
+)
+
+func init() {
+	// errors are not possible there cause registry is empty
+	RegisterFormat("hjson", func(settings ...interface{}) (IConfig, error) {
+		c, err := NewHJSONConfig(settings...)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}, "json")
+	RegisterFormat("yaml", func(settings ...interface{}) (IConfig, error) {
+		c, err := NewYAMLConfig(settings...)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}, "yml")
+	RegisterFormat("toml", func(settings ...interface{}) (IConfig, error) {
+		c, err := NewTOMLConfig(settings...)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	})
+	RegisterFormat("ini", func(settings ...interface{}) (IConfig, error) {
+		c, err := NewINIConfig(settings...)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	})
+	RegisterFormat("properties", func(settings ...interface{}) (IConfig, error) {
+		c, err := NewPropertiesConfig(settings...)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	})
+	RegisterFormat(AutoFormat, detectAndLoad)
+	RegisterFormatDetection("hjson", sniffHJSON, ".hjson", ".json")
+	RegisterFormatDetection("yaml", sniffYAML, ".yaml", ".yml")
+	RegisterFormatDetection("toml", sniffTOML, ".toml")
+	RegisterFormatDetection("ini", sniffINI, ".ini")
+	RegisterFormatDetection("properties", sniffProperties, ".properties")
+}
+
+// RegisterFormat registers configuration format for GetConfigInstance
+// name and aliases are case insensitive: RegisterFormat("hjson", f, "json") makes
+// HJSON, hjson, JSON and json format names work.
+// Returns ConfigUsageError if name or some alias is already registered
+func RegisterFormat(name string, factory FormatFactory, aliases ...string) error {
+	if "" == name {
+		return NewConfigUsageError("Format name must not be empty")
+	}
+	if nil == factory {
+		return NewConfigUsageError("Format factory must not be nil for format " + name)
+	}
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	keys := make([]string, 0, len(aliases)+1)
+	for _, n := range append([]string{name}, aliases...) {
+		key := strings.ToLower(n)
+		if "" == key {
+			return NewConfigUsageError("Format alias must not be empty for format " + name)
+		}
+		if _, ok := formatsByName[key]; ok {
+			return NewConfigUsageError("Format " + n + " is already registered")
+		}
+		for _, k := range keys {
+			if k == key {
+				return NewConfigUsageError("Format " + n + " is given twice")
+			}
+		}
+		keys = append(keys, key)
+	}
+	f := &registeredFormat{name: name, factory: factory}
+	for _, key := range keys {
+		formatsByName[key] = f
+	}
+	formatsList = append(formatsList, f)
+	return nil
+}
+
+// ListFormats returns sorted names of registered formats. aliases are not listed
+func ListFormats() []string {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	names := make([]string, 0, len(formatsList))
+	for _, f := range formatsList {
+		names = append(names, f.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getFormatFactory finds format factory by case insensitive name or alias
+func getFormatFactory(name string) (FormatFactory, bool) {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	f, ok := formatsByName[strings.ToLower(name)]
+	if !ok {
+		return nil, false
+	}
+	return f.factory, true
+}
@@ -0,0 +1,122 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	factory := func(settings ...interface{}) (IConfig, error) {
+		return NewHJSONConfig(map[string]interface{}{"custom": true})
+	}
+	type args struct {
+		name    string
+		factory FormatFactory
+		aliases []string
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name:        "empty name",
+			args:        args{name: "", factory: factory},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "nil factory",
+			args:        args{name: "testformat_nil", factory: nil},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "builtin name registered twice",
+			args:        args{name: "HJSON", factory: factory},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "builtin alias registered twice",
+			args:        args{name: "testformat_alias", factory: factory, aliases: []string{"YML"}},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "same alias given twice",
+			args:        args{name: "testformat_twice", factory: factory, aliases: []string{"TestFormat_Twice"}},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:    "normal registration",
+			args:    args{name: "testformat", factory: factory, aliases: []string{"tf"}},
+			wantErr: false,
+		},
+		{
+			name:        "custom format registered twice",
+			args:        args{name: "TESTFORMAT", factory: factory},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterFormat(tt.args.name, tt.args.factory, tt.args.aliases...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("RegisterFormat() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+		})
+	}
+	// failed registrations must not leave anything in registry
+	for _, name := range []string{"testformat_alias", "testformat_twice", "testformat_nil"} {
+		if _, ok := getFormatFactory(name); ok {
+			t.Errorf("format %v is registered after failed RegisterFormat()", name)
+		}
+	}
+	for _, name := range []string{"testformat", "TestFormat", "TF"} {
+		c, err := GetConfigInstance(nil, name)
+		if err != nil {
+			t.Errorf("GetConfigInstance() with format %v error = %v", name, err)
+			continue
+		}
+		b, err := c.GetBooleanValue("custom")
+		if err != nil || !b {
+			t.Errorf("GetConfigInstance() with format %v returned config not made by custom factory", name)
+		}
+	}
+}
+
+func TestListFormats(t *testing.T) {
+	got := ListFormats()
+	for _, want := range []string{"hjson", "ini", "properties", "toml", "yaml"} {
+		found := false
+		for _, name := range got {
+			if name == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("ListFormats() = %v, %v is not listed", got, want)
+		}
+	}
+	for _, alias := range []string{"json", "yml"} {
+		for _, name := range got {
+			if name == alias {
+				t.Errorf("ListFormats() = %v, alias %v must not be listed", got, alias)
+			}
+		}
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1] > got[i] {
+			t.Errorf("ListFormats() = %v is not sorted", got)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package configuration
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetDurationValue returns time.Duration by path.
+// Strings are parsed by time.ParseDuration("30s", "1h30m"), numbers are seconds like in Unmarshal
+func (fl *HJSONConfig) GetDurationValue(path ...string) (d time.Duration, err error) {
+	i1, err1 := fl.GetValue(path...)
+	if nil != err1 {
+		return 0, err1
+	}
+	d, err = durationValue(i1)
+	if nil != err {
+		return 0, NewConfigTypeMismatchError(pathString(path) + ": " + err.Error())
+	}
+	return d, nil
+}
+
+// durationValue converts "30s" strings or numbers of seconds to time.Duration
+func durationValue(val interface{}) (time.Duration, error) {
+	if s, ok := val.(string); ok {
+		return time.ParseDuration(s)
+	}
+	f, ok := numberValue(val)
+	if !ok {
+		return 0, fmt.Errorf("expected duration, got %T", val)
+	}
+	ns := f * float64(time.Second)
+	if math.IsNaN(ns) || ns < math.MinInt64 || ns >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration %v seconds is out of range", f)
+	}
+	return time.Duration(ns), nil
+}
+
+// byteSizeUnits are multipliers of size units. SI units are powers of 1000, IEC ones powers of 1024
+var byteSizeUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"eb":  1e18,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+	"eib": 1 << 60,
+}
+
+// ParseByteSize parses human readable size like "10MiB", "1.5 GB" or "512" into bytes.
+// SI units(kB, MB, GB, TB, PB, EB) are powers of 1000, IEC ones(KiB, MiB, GiB, TiB, PiB, EiB)
+// are powers of 1024. Units are case insensitive. Result must be whole number of bytes,
+// it's checked on exact decimal value, so "2.01GB" is 2010000000 bytes
+func ParseByteSize(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && strings.ContainsRune("0123456789.", rune(s[i])) {
+		i++
+	}
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	r, ok := new(big.Rat).SetString(num)
+	if !ok {
+		return 0, fmt.Errorf("wrong size %q", s)
+	}
+	mul, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("wrong size %q: unknown unit %q", s, s[i:])
+	}
+	r.Mul(r, new(big.Rat).SetUint64(mul))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("size %v is not whole number of bytes", s)
+	}
+	if !r.Num().IsUint64() {
+		return 0, fmt.Errorf("size %v is out of range", s)
+	}
+	return r.Num().Uint64(), nil
+}
+
+// byteSizeValue checks number of bytes. s is original value for error messages
+func byteSizeValue(f float64, s interface{}) (uint64, error) {
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("size %v is not whole number of bytes", s)
+	}
+	if f < 0 || f >= 1<<64 {
+		return 0, fmt.Errorf("size %v is out of range", s)
+	}
+	return uint64(f), nil
+}
+
+// GetByteSizeValue returns size in bytes by path.
+// Strings are parsed by ParseByteSize("10MiB", "1.5GB"), numbers are bytes
+func (fl *HJSONConfig) GetByteSizeValue(path ...string) (size uint64, err error) {
+	i1, err1 := fl.GetValue(path...)
+	if nil != err1 {
+		return 0, err1
+	}
+	switch v := i1.(type) {
+	case string:
+		size, err = ParseByteSize(v)
+	default:
+		f, ok := numberValue(v)
+		if !ok {
+			return 0, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected size, got %T", pathString(path), v))
+		}
+		size, err = byteSizeValue(f, f)
+	}
+	if nil != err {
+		return 0, NewConfigTypeMismatchError(pathString(path) + ": " + err.Error())
+	}
+	return size, nil
+}
+
+// SetTimeLayouts sets time.Parse layouts GetTimeValue tries after RFC3339, e.g. "2006-01-02"
+func (fl *HJSONConfig) SetTimeLayouts(layouts ...string) {
+	l := make([]string, len(layouts))
+	copy(l, layouts)
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+	fl.options.timeLayouts = l
+}
+
+// GetTimeValue returns time.Time by path.
+// Strings are parsed as RFC3339 or by layouts set with SetTimeLayouts,
+// numbers are unix time in seconds, TOML datetime values are returned as is
+func (fl *HJSONConfig) GetTimeValue(path ...string) (tm time.Time, err error) {
+	i1, err1 := fl.GetValue(path...)
+	if nil != err1 {
+		return time.Time{}, err1
+	}
+	switch v := i1.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		tm, err = time.Parse(time.RFC3339, v)
+		if nil == err {
+			return tm, nil
+		}
+		for _, layout := range fl.getOptions().timeLayouts {
+			if tm, e := time.Parse(layout, v); nil == e {
+				return tm, nil
+			}
+		}
+		return time.Time{}, NewConfigTypeMismatchError(pathString(path) + ": " + err.Error())
+	default:
+		f, ok := numberValue(v)
+		if !ok {
+			return time.Time{}, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected time, got %T", pathString(path), v))
+		}
+		sec, frac := math.Modf(f)
+		if math.IsNaN(f) || sec < math.MinInt64 || sec >= math.MaxInt64 {
+			return time.Time{}, NewConfigTypeMismatchError(fmt.Sprintf("%s: unix time %v is out of range", pathString(path), f))
+		}
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(), nil
+	}
+}
+
+// sliceItems gives array items by path. String value is split by commas cause
+// values overridden by environment or flags may come as "a,b,c"
+func (fl *HJSONConfig) sliceItems(path []string) ([]interface{}, error) {
+	i1, err := fl.GetValue(path...)
+	if nil != err {
+		return nil, err
+	}
+	switch v := i1.(type) {
+	case []interface{}:
+		return v, nil
+	case string:
+		l := []interface{}{}
+		if "" == strings.TrimSpace(v) {
+			return l, nil
+		}
+		for _, item := range strings.Split(v, ",") {
+			l = append(l, strings.TrimSpace(item))
+		}
+		return l, nil
+	default:
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected array, got %T", pathString(path), v))
+	}
+}
+
+// GetStringSlice returns array of strings by path. "a,b,c" string is array too
+func (fl *HJSONConfig) GetStringSlice(path ...string) (res []string, err error) {
+	l, err := fl.sliceItems(path)
+	if nil != err {
+		return nil, err
+	}
+	res = make([]string, len(l))
+	for i, item := range l {
+		s, ok := item.(string)
+		if !ok {
+			return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected string, got %T", pathString(appendPath(path, strconv.Itoa(i))), item))
+		}
+		res[i] = s
+	}
+	return res, nil
+}
+
+// numberItem gives number array item. strings come from comma separated value
+func numberItem(item interface{}, path []string) (interface{}, error) {
+	if s, ok := item.(string); ok {
+		n, err := parseNumber(s)
+		if err != nil {
+			return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected number, got %q", pathString(path), s))
+		}
+		return n, nil
+	}
+	if _, ok := numberValue(item); !ok {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected number, got %T", pathString(path), item))
+	}
+	return item, nil
+}
+
+// floatItem gives number array item as float64
+func floatItem(item interface{}, path []string) (float64, error) {
+	n, err := numberItem(item, path)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := numberValue(n)
+	return f, nil
+}
+
+// GetFloatSlice returns array of numbers by path. "1,2.5" string is array too
+func (fl *HJSONConfig) GetFloatSlice(path ...string) (res []float64, err error) {
+	l, err := fl.sliceItems(path)
+	if nil != err {
+		return nil, err
+	}
+	res = make([]float64, len(l))
+	for i, item := range l {
+		if res[i], err = floatItem(item, appendPath(path, strconv.Itoa(i))); nil != err {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// GetIntSlice returns array of integers by path. "1,2,3" string is array too.
+// Items are checked like GetIntValue does: out of range ones are errors, fractional ones are errors in strict mode
+func (fl *HJSONConfig) GetIntSlice(path ...string) (res []int, err error) {
+	l, err := fl.sliceItems(path)
+	if nil != err {
+		return nil, err
+	}
+	strict := fl.getOptions().strictNumbers
+	res = make([]int, len(l))
+	for i, item := range l {
+		itemPath := appendPath(path, strconv.Itoa(i))
+		n, err := numberItem(item, itemPath)
+		if nil != err {
+			return nil, err
+		}
+		v, err := checkInteger(n, itemPath, intRange, strict)
+		if nil != err {
+			return nil, err
+		}
+		res[i] = int(v.Int64())
+	}
+	return res, nil
+}
+
+// GetBoolSlice returns array of booleans by path. "true,false" string is array too
+func (fl *HJSONConfig) GetBoolSlice(path ...string) (res []bool, err error) {
+	l, err := fl.sliceItems(path)
+	if nil != err {
+		return nil, err
+	}
+	res = make([]bool, len(l))
+	for i, item := range l {
+		switch v := item.(type) {
+		case bool:
+			res[i] = v
+		case string:
+			if res[i], err = strconv.ParseBool(v); nil != err {
+				return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected boolean, got %q", pathString(appendPath(path, strconv.Itoa(i))), v))
+			}
+		default:
+			return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected boolean, got %T", pathString(appendPath(path, strconv.Itoa(i))), item))
+		}
+	}
+	return res, nil
+}
+
+// GetStringMap returns object by path as map. It's deep copy so it may be changed by caller
+func (fl *HJSONConfig) GetStringMap(path ...string) (m map[string]interface{}, err error) {
+	i1, err1 := fl.GetValue(path...)
+	if nil != err1 {
+		return nil, err1
+	}
+	v, ok := i1.(map[string]interface{})
+	if !ok {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected object, got %T", pathString(path), i1))
+	}
+	return deepCopyValue(v).(map[string]interface{}), nil
+}
+
+// GetStringMapString returns object with string values by path as map
+func (fl *HJSONConfig) GetStringMapString(path ...string) (m map[string]string, err error) {
+	i1, err1 := fl.GetValue(path...)
+	if nil != err1 {
+		return nil, err1
+	}
+	v, ok := i1.(map[string]interface{})
+	if !ok {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected object, got %T", pathString(path), i1))
+	}
+	m = make(map[string]string, len(v))
+	for k, item := range v {
+		s, ok := item.(string)
+		if !ok {
+			return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: expected string, got %T", pathString(appendPath(path, k)), item))
+		}
+		m[k] = s
+	}
+	return m, nil
+}
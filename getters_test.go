@@ -0,0 +1,382 @@
+package configuration
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHJSONConfig_GetDurationValue(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{
+		"timeout":  "30s",
+		"complex":  "1h30m",
+		"seconds":  1.5,
+		"native":   10,
+		"wrong":    "30 seconds",
+		"flag":     true,
+		"enormous": 1e300,
+	}}
+	type teststruct struct {
+		name        string
+		path        string
+		want        time.Duration
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "string", path: "timeout", want: 30 * time.Second},
+		{name: "complex string", path: "complex", want: 90 * time.Minute},
+		{name: "seconds", path: "seconds", want: 1500 * time.Millisecond},
+		{name: "int seconds", path: "native", want: 10 * time.Second},
+		{name: "wrong string", path: "wrong", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "wrong type", path: "flag", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "out of range", path: "enormous", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "absent", path: "nothing", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fl.GetDurationValue(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig.GetDurationValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("HJSONConfig.GetDurationValue() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+			if tt.wantErr && "*configuration.ConfigTypeMismatchError" == tt.wantErrType && !strings.HasPrefix(err.Error(), tt.path+": ") {
+				t.Errorf("HJSONConfig.GetDurationValue() error = %v, want path in it", err)
+			}
+			if got != tt.want {
+				t.Errorf("HJSONConfig.GetDurationValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	type teststruct struct {
+		name    string
+		s       string
+		want    uint64
+		wantErr bool
+	}
+	tests := []teststruct{
+		{name: "bytes", s: "512", want: 512},
+		{name: "bytes unit", s: "512B", want: 512},
+		{name: "SI kilo", s: "10kB", want: 10000},
+		{name: "SI mega with space", s: "10 MB", want: 10000000},
+		{name: "SI giga fraction", s: "1.5GB", want: 1500000000},
+		{name: "SI exa", s: "1EB", want: 1000000000000000000},
+		{name: "SI giga exact decimal", s: "2.01GB", want: 2010000000},
+		{name: "SI kilo exact decimal", s: "1.005kB", want: 1005},
+		{name: "IEC kibi", s: "1KiB", want: 1024},
+		{name: "IEC mebi", s: "10MiB", want: 10 << 20},
+		{name: "IEC gibi fraction", s: "1.5GiB", want: 3 << 29},
+		{name: "IEC tebi", s: "2TiB", want: 2 << 40},
+		{name: "IEC exbi", s: "15EiB", want: 15 << 60},
+		{name: "case insensitive", s: "10mib", want: 10 << 20},
+		{name: "out of range", s: "16EiB", wantErr: true},
+		{name: "not whole bytes", s: "1.5B", wantErr: true},
+		{name: "unknown unit", s: "10MiBs", wantErr: true},
+		{name: "negative", s: "-1KB", wantErr: true},
+		{name: "no number", s: "MB", wantErr: true},
+		{name: "empty", s: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseByteSize(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_GetByteSizeValue(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{
+		"max_body": "10MiB",
+		"buffer":   4096.0,
+		"fraction": 1.5,
+		"negative": -1.0,
+		"wrong":    "10 parsecs",
+		"flag":     true,
+	}}
+	type teststruct struct {
+		name        string
+		path        string
+		want        uint64
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "string", path: "max_body", want: 10 << 20},
+		{name: "number", path: "buffer", want: 4096},
+		{name: "fractional number", path: "fraction", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "negative number", path: "negative", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "wrong string", path: "wrong", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "wrong type", path: "flag", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "absent", path: "nothing", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fl.GetByteSizeValue(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig.GetByteSizeValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("HJSONConfig.GetByteSizeValue() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+			if tt.wantErr && "*configuration.ConfigTypeMismatchError" == tt.wantErrType && !strings.HasPrefix(err.Error(), tt.path+": ") {
+				t.Errorf("HJSONConfig.GetByteSizeValue() error = %v, want path in it", err)
+			}
+			if got != tt.want {
+				t.Errorf("HJSONConfig.GetByteSizeValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_GetTimeValue(t *testing.T) {
+	tomlTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{
+		"cutoff":  "2026-01-01T00:00:00Z",
+		"zoned":   "2026-01-01T03:00:00.5+03:00",
+		"date":    "2026-01-01",
+		"unix":    1767225600.0,
+		"unixfr":  1767225600.25,
+		"toml":    tomlTime,
+		"wrong":   "yesterday",
+		"flag":    true,
+		"huge":    1e300,
+		"section": map[string]interface{}{"date": "2026-01-01"},
+	}}
+	type teststruct struct {
+		name        string
+		layouts     []string
+		path        []string
+		want        time.Time
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "RFC3339", path: []string{"cutoff"}, want: tomlTime},
+		{name: "RFC3339 with zone and fraction", path: []string{"zoned"}, want: tomlTime.Add(500 * time.Millisecond)},
+		{name: "unix seconds", path: []string{"unix"}, want: tomlTime},
+		{name: "unix fractional seconds", path: []string{"unixfr"}, want: tomlTime.Add(250 * time.Millisecond)},
+		{name: "time value", path: []string{"toml"}, want: tomlTime},
+		{name: "layout not set", path: []string{"date"}, wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "layout", layouts: []string{time.RFC1123, "2006-01-02"}, path: []string{"date"}, want: tomlTime},
+		{name: "layout in subconfig", layouts: []string{"2006-01-02"}, path: []string{"section", "date"}, want: tomlTime},
+		{name: "wrong string", layouts: []string{"2006-01-02"}, path: []string{"wrong"}, wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "wrong type", path: []string{"flag"}, wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "out of range", path: []string{"huge"}, wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "absent", path: []string{"nothing"}, wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl.SetTimeLayouts(tt.layouts...)
+			var c IConfig = fl
+			path := tt.path
+			if len(path) > 1 {
+				// layouts are inherited by subconfig
+				sub, err := fl.GetSubconfig(path[:len(path)-1]...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				c, path = sub, path[len(path)-1:]
+			}
+			got, err := c.GetTimeValue(path...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig.GetTimeValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("HJSONConfig.GetTimeValue() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("HJSONConfig.GetTimeValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_sliceGetters(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{
+		"strings":  []interface{}{"a", "b"},
+		"numbers":  []interface{}{1.0, 2.5, 3},
+		"ints":     []interface{}{1.0, 2.0, -3.0},
+		"bools":    []interface{}{true, false},
+		"mixed":    []interface{}{"a", 2.0, true},
+		"huge":     []interface{}{1.0, 1e300},
+		"csv":      "a, b,c",
+		"csvints":  "1, 2,3",
+		"csvbools": "true,false",
+		"csvwrong": "1,x",
+		"empty":    "",
+		"scalar":   5.0,
+	}}
+	type teststruct struct {
+		name        string
+		get         string
+		path        string
+		want        interface{}
+		wantErr     bool
+		wantErrType string
+		wantInError string
+	}
+	tests := []teststruct{
+		{name: "strings", get: "string", path: "strings", want: []string{"a", "b"}},
+		{name: "strings csv", get: "string", path: "csv", want: []string{"a", "b", "c"}},
+		{name: "strings empty csv", get: "string", path: "empty", want: []string{}},
+		{name: "strings wrong item", get: "string", path: "mixed", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: "mixed.1: expected string"},
+		{name: "strings not array", get: "string", path: "scalar", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: "scalar: expected array"},
+		{name: "strings absent", get: "string", path: "nothing", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+		{name: "floats", get: "float", path: "numbers", want: []float64{1, 2.5, 3}},
+		{name: "floats csv", get: "float", path: "csvints", want: []float64{1, 2, 3}},
+		{name: "floats wrong item", get: "float", path: "mixed", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: "mixed.0: expected number"},
+		{name: "floats wrong csv item", get: "float", path: "csvwrong", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: `csvwrong.1: expected number, got "x"`},
+		{name: "ints", get: "int", path: "ints", want: []int{1, 2, -3}},
+		{name: "ints truncated", get: "int", path: "numbers", want: []int{1, 2, 3}},
+		{name: "ints csv", get: "int", path: "csvints", want: []int{1, 2, 3}},
+		{name: "ints overflow", get: "int", path: "huge", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: "huge.1: value 1e+300 does not fit int"},
+		{name: "ints wrong item", get: "int", path: "strings", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: "strings.0: expected number"},
+		{name: "bools", get: "bool", path: "bools", want: []bool{true, false}},
+		{name: "bools csv", get: "bool", path: "csvbools", want: []bool{true, false}},
+		{name: "bools wrong item", get: "bool", path: "mixed", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: "mixed.0: expected boolean"},
+		{name: "bools wrong csv item", get: "bool", path: "csvwrong", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError", wantInError: `csvwrong.1: expected boolean, got "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got interface{}
+			var err error
+			switch tt.get {
+			case "string":
+				got, err = fl.GetStringSlice(tt.path)
+			case "float":
+				got, err = fl.GetFloatSlice(tt.path)
+			case "int":
+				got, err = fl.GetIntSlice(tt.path)
+			case "bool":
+				got, err = fl.GetBoolSlice(tt.path)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig %s slice getter error = %v, wantErr %v", tt.get, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if "" != tt.wantErrType && tt.wantErrType != reflect.TypeOf(err).String() {
+					t.Errorf("HJSONConfig %s slice getter error type = %v, wantErrType %v", tt.get, reflect.TypeOf(err), tt.wantErrType)
+				}
+				if !strings.Contains(err.Error(), tt.wantInError) {
+					t.Errorf("HJSONConfig %s slice getter error = %v, want it contains %v", tt.get, err, tt.wantInError)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HJSONConfig %s slice getter = %#v, want %#v", tt.get, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_GetIntSlice_strict(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{"numbers": []interface{}{1.0, 2.5}}}
+	fl.SetStrictNumbers(true)
+	if _, err := fl.GetIntSlice("numbers"); nil == err || !strings.Contains(err.Error(), "numbers.1: value 2.5 is not integer") {
+		t.Errorf("HJSONConfig.GetIntSlice() error = %v, want numbers.1 is not integer", err)
+	}
+}
+
+func TestHJSONConfig_mapGetters(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{
+		"labels": map[string]interface{}{"app": "web", "tier": "front"},
+		"mixed":  map[string]interface{}{"app": "web", "replicas": 3.0, "list": []interface{}{1.0}},
+		"scalar": "text",
+	}}
+	m, err := fl.GetStringMap("mixed")
+	if err != nil || !reflect.DeepEqual(m, map[string]interface{}{"app": "web", "replicas": 3.0, "list": []interface{}{1.0}}) {
+		t.Errorf("HJSONConfig.GetStringMap() = %v, %v", m, err)
+	}
+	// caller's changes do not go to config
+	m["app"] = "changed"
+	m["list"].([]interface{})[0] = 2.0
+	if s, _ := fl.GetStringValue("mixed", "app"); s != "web" {
+		t.Errorf("HJSONConfig.GetStringMap() result shares memory with config")
+	}
+	if v, _ := fl.GetFloatSlice("mixed", "list"); v[0] != 1 {
+		t.Errorf("HJSONConfig.GetStringMap() result shares memory with config")
+	}
+	ms, err := fl.GetStringMapString("labels")
+	if err != nil || !reflect.DeepEqual(ms, map[string]string{"app": "web", "tier": "front"}) {
+		t.Errorf("HJSONConfig.GetStringMapString() = %v, %v", ms, err)
+	}
+	type teststruct struct {
+		name        string
+		get         func() error
+		wantErrType string
+		wantInError string
+	}
+	tests := []teststruct{
+		{
+			name:        "GetStringMap of scalar",
+			get:         func() error { _, err := fl.GetStringMap("scalar"); return err },
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+			wantInError: "scalar: expected object",
+		},
+		{
+			name:        "GetStringMap absent",
+			get:         func() error { _, err := fl.GetStringMap("nothing"); return err },
+			wantErrType: "*configuration.ConfigItemNotFound",
+		},
+		{
+			name:        "GetStringMapString of scalar",
+			get:         func() error { _, err := fl.GetStringMapString("scalar"); return err },
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+			wantInError: "scalar: expected object",
+		},
+		{
+			name:        "GetStringMapString not string value",
+			get:         func() error { _, err := fl.GetStringMapString("mixed"); return err },
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+			wantInError: "expected string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.get()
+			if nil == err {
+				t.Fatalf("%s error = nil, want %s", tt.name, tt.wantErrType)
+			}
+			if tt.wantErrType != reflect.TypeOf(err).String() {
+				t.Errorf("%s error type = %v, wantErrType %v", tt.name, reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !strings.Contains(err.Error(), tt.wantInError) {
+				t.Errorf("%s error = %v, want it contains %v", tt.name, err, tt.wantInError)
+			}
+		})
+	}
+}
+
+func TestEnvConfig_GetStringSlice(t *testing.T) {
+	base, err := NewHJSONConfig(map[string]interface{}{"hosts": "localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("SLICETEST_HOSTS", "a.example.com, b.example.com")
+	defer os.Unsetenv("SLICETEST_HOSTS")
+	fl, err := NewEnvConfig(base, "SLICETEST_", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := fl.GetStringSlice("hosts")
+	if err != nil || !reflect.DeepEqual(hosts, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("EnvConfig.GetStringSlice() = %v, %v", hosts, err)
+	}
+}
@@ -1,15 +1,45 @@
 package configuration
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"math"
+	"math/big"
+	"strconv"
+	"sync"
 
 	hjson "github.com/hjson/hjson-go"
 )
 
 // HJSONConfig is configuration loader HJSON interface
+// It's safe for concurrent use: reload swaps internal map under lock and
+// maps are never changed after they are set, so getters work with map they got
 type HJSONConfig struct {
+	// mutex guards filename, hjsonMap, parser and other state swapped on reload
+	mutex    sync.RWMutex
 	filename string
 	hjsonMap map[string]interface{}
+	// parser is used by other formats built on top of HJSONConfig. nil means HJSON itself
+	parser contentsParser
+	// watch keeps Watch callbacks
+	watch *watchState
+	// options are getter options. subconfigs and snapshots inherit them
+	options getterOptions
+}
+
+// getterOptions change how typed getters convert values
+type getterOptions struct {
+	// strictNumbers makes integer getters reject fractional values
+	strictNumbers bool
+	// timeLayouts are tried by GetTimeValue after RFC3339
+	timeLayouts []string
+}
+
+// contentsParser turns raw file contents into configuration map
+// formats which reuse HJSONConfig internals(e.g. YAMLConfig) implement them
+type contentsParser interface {
+	ParseStringContents(cnt []byte) (m map[string]interface{}, err error)
 }
 
 // LoadFileContents load contents of file. separate function to make tests possible
@@ -23,12 +53,86 @@ func (fl *HJSONConfig) LoadFileContents(filename string) (cnt []byte, err error)
 // ParseStringContents parses HJSON - separated to method cause I want test that
 func (fl *HJSONConfig) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
 	m = map[string]interface{}{}
-	err = hjson.Unmarshal(cnt, &m)
+	// numbers are decoded as text so big integers are not rounded to float64
+	err = hjson.UnmarshalWithOptions(cnt, &m, hjson.DecoderOptions{UseJSONNumber: true})
 	if nil != err {
 		m = nil
 		return
 	}
-	return
+	if err = checkNumbers(m, nil); nil != err {
+		return nil, err
+	}
+	return normalizeValue(m).(map[string]interface{}), nil
+}
+
+// checkNumbers finds numbers float64 can not keep, like 1e400, so they do not become Inf silently
+func checkNumbers(v interface{}, path []string) error {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if err := checkNumbers(val, appendPath(path, k)); nil != err {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, val := range t {
+			if err := checkNumbers(val, appendPath(path, strconv.Itoa(i))); nil != err {
+				return err
+			}
+		}
+	case json.Number:
+		if _, err := parseNumber(string(t)); nil != err {
+			return NewConfigParseError(fmt.Sprintf("%s: number %s is out of range", pathString(path), t))
+		}
+	}
+	return nil
+}
+
+// getMap gives current internal map
+func (fl *HJSONConfig) getMap() map[string]interface{} {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	return fl.hjsonMap
+}
+
+// setMap swaps internal map. m must not be changed after that
+func (fl *HJSONConfig) setMap(m map[string]interface{}) {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+	fl.hjsonMap = m
+}
+
+// setSource swaps internal map and name of file it's loaded from together
+func (fl *HJSONConfig) setSource(filename string, m map[string]interface{}) {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+	fl.filename = filename
+	fl.hjsonMap = m
+}
+
+// getFilename gives name of file config is loaded from. Empty if config is not file backed
+func (fl *HJSONConfig) getFilename() string {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	return fl.filename
+}
+
+// setParser sets format parser. Formats built on top of HJSONConfig call it before loading
+func (fl *HJSONConfig) setParser(p contentsParser) {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+	fl.parser = p
+}
+
+// parseContents parses file contents with format parser if any or with HJSON
+func (fl *HJSONConfig) parseContents(cnt []byte) (m map[string]interface{}, err error) {
+	fl.mutex.RLock()
+	p := fl.parser
+	fl.mutex.RUnlock()
+	if nil != p {
+		return p.ParseStringContents(cnt)
+	}
+	return fl.ParseStringContents(cnt)
 }
 
 // SetDefaultLoadSetting sets default config file for loader
@@ -43,22 +147,22 @@ func (fl *HJSONConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
 		if err != nil {
 			return NewHJSONConfigError("Error loading file occurred: " + err.Error())
 		}
-		m, err := fl.ParseStringContents(cnt)
+		m, err := fl.parseContents(cnt)
 		if err != nil {
 			return err
 		}
-		fl.filename = v
-		fl.hjsonMap = m
+		fl.setSource(v, m)
 	case []byte:
-		m, err := fl.ParseStringContents(v)
-		fl.filename = ""
+		m, err := fl.parseContents(v)
 		if err != nil {
+			fl.mutex.Lock()
+			fl.filename = ""
+			fl.mutex.Unlock()
 			return err
 		}
-		fl.hjsonMap = m
+		fl.setSource("", m)
 	case map[string]interface{}:
-		fl.filename = ""
-		fl.hjsonMap = v
+		fl.setSource("", v)
 	default:
 		return NewHJSONConfigError("HJSONConfig.SetDefaultLoadSetting() argument must be string, []byte, or map[string]interface{}")
 	}
@@ -68,78 +172,178 @@ func (fl *HJSONConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
 
 // CheckExternalConfig checks external configuration file and it's contents - e.g.check file before reload
 func (fl *HJSONConfig) CheckExternalConfig() (err error) {
-	if "" == fl.filename {
-		return NewConfigUsageError("Can not check external file cause it's not configured inside")
-	}
-	cnt, err := fl.LoadFileContents(fl.filename)
-	if err != nil {
-		return err
-	}
-	_, err = fl.ParseStringContents(cnt)
+	_, err = fl.loadExternalMap()
+	return err
+}
+
+// ReloadInternalMap (re)loads internal map - if from file. If not - says ConfigUsageError
+func (fl *HJSONConfig) ReloadInternalMap() (err error) {
+	m, err := fl.loadExternalMap()
 	if nil != err {
 		return err
 	}
+	fl.setMap(m)
 	return nil
 }
 
-// ReloadInternalMap (re)loads internal map - if from file. If not - says ConfigUsageError
-func (fl *HJSONConfig) ReloadInternalMap() (err error) {
-	if "" == fl.filename {
-		return NewConfigUsageError("Can not check external file cause it's not configured inside")
+// loadExternalMap loads and parses config file without touching internal map
+func (fl *HJSONConfig) loadExternalMap() (m map[string]interface{}, err error) {
+	filename := fl.getFilename()
+	if "" == filename {
+		return nil, NewConfigUsageError("Can not check external file cause it's not configured inside")
 	}
-	cnt, err := fl.LoadFileContents(fl.filename)
+	cnt, err := fl.LoadFileContents(filename)
 	if err != nil {
-		return err
-	}
-	m, err := fl.ParseStringContents(cnt)
-	if nil != err {
-		return err
+		return nil, err
 	}
-	fl.hjsonMap = m
-	return nil
+	return fl.parseContents(cnt)
 }
 
 // GetValue get any type value on programmer mind own
 // usage on initialized object: fl.GetValue("a", "b", "c", "d")
+// array items are addressed by index path elements: fl.GetValue("servers", "2", "host"),
+// negative index counts from the end: "-1" is the last item
 // on this function would be based functions below
 func (fl *HJSONConfig) GetValue(path ...string) (i interface{}, err error) {
-	if nil == fl.hjsonMap {
+	currentMap := fl.getMap()
+	if nil == currentMap {
 		return nil, NewConfigUsageError("No config was initialized yet")
 	}
 	if 0 == len(path) {
 		return nil, NewConfigUsageError("You must get values with path arguments there")
 	}
-	currentMap := fl.hjsonMap
-	for _, key := range path {
-		val, ok := currentMap[key]
-		if !ok {
-			return nil, NewConfigItemNotFound("Item not found")
-		}
-		switch v := val.(type) {
+	current := interface{}(currentMap)
+	for n, key := range path {
+		switch c := current.(type) {
 		case map[string]interface{}:
-			currentMap = v
+			v, ok := c[key]
+			if !ok {
+				return nil, NewConfigItemNotFound("Item not found")
+			}
+			current = v
+		case []interface{}:
+			current, err = arrayItem(c, key)
+			if err != nil {
+				return nil, err
+			}
 		default:
-			return interface{}(v), nil
+			// path is longer than data: do not give scalar as answer for deeper path
+			return nil, NewConfigTypeMismatchError(fmt.Sprintf("Can not get %q: %s is %T, not object or array", key, pathString(path[:n]), c))
 		}
 	}
-	// here stays 1 algorithmic variant - currentMap is answer itself
-	return interface{}(currentMap), nil
+	return current, nil
+}
+
+// arrayItem gives array item by index path element. Negative index counts from the end
+func arrayItem(l []interface{}, key string) (interface{}, error) {
+	idx, err := strconv.Atoi(key)
+	if err != nil {
+		return nil, NewConfigItemNotFound("Item not found: " + key + " is not array index")
+	}
+	if idx < 0 {
+		idx += len(l)
+	}
+	if idx < 0 || idx >= len(l) {
+		return nil, NewConfigItemNotFound(fmt.Sprintf("Item not found: index %s is out of range of %d items", key, len(l)))
+	}
+	return l[idx], nil
+}
+
+// SetStrictNumbers turns strict mode for integer getters on or off.
+// In strict mode fractional values like 1.9 are ConfigTypeMismatchError,
+// otherwise they are truncated to integer. Values out of integer range are errors always
+func (fl *HJSONConfig) SetStrictNumbers(strict bool) {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+	fl.options.strictNumbers = strict
+}
+
+// getOptions gives getter options
+func (fl *HJSONConfig) getOptions() getterOptions {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	return fl.options
 }
 
 // GetIntValue returns integer value by path
 func (fl *HJSONConfig) GetIntValue(path ...string) (i int, err error) {
+	n, err := fl.integerValue(path, intRange)
+	if nil != err {
+		return 0, err
+	}
+	return int(n.Int64()), nil
+}
+
+// GetInt64Value returns int64 value by path
+func (fl *HJSONConfig) GetInt64Value(path ...string) (i int64, err error) {
+	n, err := fl.integerValue(path, int64Range)
+	if nil != err {
+		return 0, err
+	}
+	return n.Int64(), nil
+}
+
+// GetUint64Value returns uint64 value by path. negative values are ConfigTypeMismatchError
+func (fl *HJSONConfig) GetUint64Value(path ...string) (i uint64, err error) {
+	n, err := fl.integerValue(path, uint64Range)
+	if nil != err {
+		return 0, err
+	}
+	return n.Uint64(), nil
+}
+
+// GetFloatValue returns float64 value by path
+func (fl *HJSONConfig) GetFloatValue(path ...string) (f float64, err error) {
 	i1, err1 := fl.GetValue(path...)
 	if nil != err1 {
 		return 0, err1
 	}
-	switch v := i1.(type) {
-	case float64:
-		return int(v), nil
-	case int:
-		return int(v), nil
-	default:
+	f, ok := numberValue(i1)
+	if !ok {
 		return 0, NewConfigTypeMismatchError("Wrong value type detected")
 	}
+	return f, nil
+}
+
+// integerRange is range of integer type values are checked against
+type integerRange struct {
+	name     string
+	min, max *big.Int
+}
+
+var (
+	intRange    = integerRange{"int", big.NewInt(-1 << (strconv.IntSize - 1)), big.NewInt(1<<(strconv.IntSize-1) - 1)}
+	int64Range  = integerRange{"int64", big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64)}
+	uint64Range = integerRange{"uint64", big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64)}
+)
+
+// integerValue gets number by path checking it fits integer range.
+// fractional part is cut or is an error in strict mode
+func (fl *HJSONConfig) integerValue(path []string, r integerRange) (*big.Int, error) {
+	i1, err := fl.GetValue(path...)
+	if nil != err {
+		return nil, err
+	}
+	if _, ok := numberValue(i1); !ok {
+		return nil, NewConfigTypeMismatchError("Wrong value type detected")
+	}
+	return checkInteger(i1, path, r, fl.getOptions().strictNumbers)
+}
+
+// checkInteger checks number is integer fitting range. path is used for error messages.
+// Range is checked on exact integer, so int64 and uint64 values are not rounded by float64
+func checkInteger(val interface{}, path []string, r integerRange, strict bool) (*big.Int, error) {
+	n, whole, ok := exactInteger(val)
+	if !ok {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: value %v is not %s", pathString(path), val, r.name))
+	}
+	if !whole && strict {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: value %v is not integer", pathString(path), val))
+	}
+	if n.Cmp(r.min) < 0 || n.Cmp(r.max) > 0 {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: value %v does not fit %s", pathString(path), val, r.name))
+	}
+	return n, nil
 }
 
 // GetStringValue returns string value or error by path
@@ -178,12 +382,33 @@ func (fl *HJSONConfig) GetSubconfig(path ...string) (c IConfig, err error) {
 	}
 	switch v := i1.(type) {
 	case map[string]interface{}:
-		return &HJSONConfig{filename: "", hjsonMap: v}, nil
+		return &HJSONConfig{filename: "", hjsonMap: v, options: fl.getOptions()}, nil
 	default:
 		return nil, NewConfigTypeMismatchError("Wrong value type detected")
 	}
 }
 
+// Unmarshal decodes value by path into target. If path is empty whole config is decoded
+// target must be not nil pointer. Struct fields are bound by `config:"name"` tags,
+// fields without tag by field name. `config:"-"` skips field.
+// Nested structs, slices, maps, pointers and embedded structs are supported.
+// Absent keys are filled from `default:"8080"` tags, `required:"true"` makes absent key an error.
+// All field problems are returned at once as ConfigBindError
+func (fl *HJSONConfig) Unmarshal(target interface{}, path ...string) (err error) {
+	m := fl.getMap()
+	if nil == m {
+		return NewConfigUsageError("No config was initialized yet")
+	}
+	val := interface{}(m)
+	if len(path) > 0 {
+		val, err = fl.GetValue(path...)
+		if nil != err {
+			return err
+		}
+	}
+	return unmarshalValue(val, target, path)
+}
+
 // NewHJSONConfig creates new object or gives err0r
 // all arguments are same as HJSONConfig.SetDefaultLoadSetting
 func NewHJSONConfig(sl ...interface{}) (fl *HJSONConfig, err error) {
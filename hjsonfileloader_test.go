@@ -3,7 +3,13 @@ package configuration
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -116,6 +122,14 @@ func TestHJSONConfig_ParseStringContents(t *testing.T) {
 			wantErr:     false,
 			wantErrType: "",
 		},
+		{
+			name:        "Number out of float64 range",
+			fields:      fields{filename: "", hjsonMap: nil},
+			args:        args{cnt: []byte("{limits: {max: [1, 1e400]}}")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -512,6 +526,165 @@ func TestHJSONConfig_GetValue(t *testing.T) {
 			wantErr:     false,
 			wantErrType: "",
 		},
+		{
+			name: "path longer than data",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{"test": interface{}("test text")},
+			},
+			args: args{
+				path: []string{"test", "nonexistent", "deeper"},
+			},
+			wantI:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name: "path goes through null",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{"item1": map[string]interface{}{"item2": nil}},
+			},
+			args: args{
+				path: []string{"item1", "item2", "item3"},
+			},
+			wantI:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name: "array item extraction",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "2", "host"},
+			},
+			wantI:       interface{}("c"),
+			wantErr:     false,
+			wantErrType: "",
+		},
+		{
+			name: "negative array index",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "-3", "host"},
+			},
+			wantI:       interface{}("a"),
+			wantErr:     false,
+			wantErrType: "",
+		},
+		{
+			name: "nested array item",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "-1", "ports", "1"},
+			},
+			wantI:       interface{}(443.0),
+			wantErr:     false,
+			wantErrType: "",
+		},
+		{
+			name: "whole array item",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "0"},
+			},
+			wantI:       interface{}(map[string]interface{}{"host": "a"}),
+			wantErr:     false,
+			wantErrType: "",
+		},
+		{
+			name: "array index out of range",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "3", "host"},
+			},
+			wantI:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigItemNotFound",
+		},
+		{
+			name: "negative array index out of range",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "-4"},
+			},
+			wantI:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigItemNotFound",
+		},
+		{
+			name: "not index path element for array",
+			fields: fields{
+				filename: "",
+				hjsonMap: map[string]interface{}{
+					"servers": []interface{}{
+						map[string]interface{}{"host": "a"},
+						map[string]interface{}{"host": "b"},
+						map[string]interface{}{"host": "c", "ports": []interface{}{80.0, 443.0}},
+					},
+				},
+			},
+			args: args{
+				path: []string{"servers", "host"},
+			},
+			wantI:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigItemNotFound",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -528,6 +701,9 @@ func TestHJSONConfig_GetValue(t *testing.T) {
 				t.Errorf("HJSONConfig.GetValue() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("HJSONConfig.GetValue() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
 			if !reflect.DeepEqual(gotI, tt.wantI) {
 				t.Errorf("HJSONConfig.GetValue() = %v, want %v", gotI, tt.wantI)
 			}
@@ -1170,3 +1346,328 @@ func TestNewHJSONConfig(t *testing.T) {
 		})
 	}
 }
+
+// run with -race to see it is really safe
+func TestHJSONConfig_concurrentReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configuration-concurrent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "app.hjson")
+	if err = ioutil.WriteFile(filename, []byte("{\n  section: {\n    value: 1\n  }\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fl, err := NewHJSONConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := fl.SetDefaultLoadSetting(filename); err != nil {
+					t.Errorf("HJSONConfig.SetDefaultLoadSetting() error = %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := fl.ReloadInternalMap(); err != nil {
+					t.Errorf("HJSONConfig.ReloadInternalMap() error = %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if v, err := fl.GetIntValue("section", "value"); err != nil || v != 1 {
+					t.Errorf("HJSONConfig.GetIntValue() = %v, %v, want 1", v, err)
+					return
+				}
+				sub, err := fl.GetSubconfig("section")
+				if err != nil {
+					t.Errorf("HJSONConfig.GetSubconfig() error = %v", err)
+					return
+				}
+				var m map[string]int
+				if err = sub.Unmarshal(&m); err != nil || m["value"] != 1 {
+					t.Errorf("HJSONConfig.Unmarshal() = %v, %v, want value 1", m, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// all getters built on GetValue must report path going through scalar
+func TestHJSONConfig_scalarInPath(t *testing.T) {
+	fl, err := NewHJSONConfig(map[string]interface{}{
+		"test": "test text",
+		"section": map[string]interface{}{
+			"value":   5.0,
+			"enabled": true,
+			"list":    []interface{}{1.0, "two"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type teststruct struct {
+		name        string
+		get         func(path ...string) error
+		path        []string
+		wantErrType string
+		wantInError string
+	}
+	getters := map[string]func(path ...string) error{
+		"GetValue": func(path ...string) error {
+			_, err := fl.GetValue(path...)
+			return err
+		},
+		"GetIntValue": func(path ...string) error {
+			_, err := fl.GetIntValue(path...)
+			return err
+		},
+		"GetStringValue": func(path ...string) error {
+			_, err := fl.GetStringValue(path...)
+			return err
+		},
+		"GetBooleanValue": func(path ...string) error {
+			_, err := fl.GetBooleanValue(path...)
+			return err
+		},
+		"GetSubconfig": func(path ...string) error {
+			_, err := fl.GetSubconfig(path...)
+			return err
+		},
+		"Unmarshal": func(path ...string) error {
+			var v interface{}
+			return fl.Unmarshal(&v, path...)
+		},
+		"GetValueByPath": func(path ...string) error {
+			_, err := fl.GetValueByPath(strings.Join(path, "."))
+			return err
+		},
+		"GetValueByPointer": func(path ...string) error {
+			_, err := fl.GetValueByPointer("/" + strings.Join(path, "/"))
+			return err
+		},
+		"Snapshot().GetValue": func(path ...string) error {
+			_, err := fl.Snapshot().GetValue(path...)
+			return err
+		},
+	}
+	tests := []teststruct{}
+	for name, get := range getters {
+		tests = append(tests,
+			teststruct{
+				name:        name + " through string",
+				get:         get,
+				path:        []string{"test", "nonexistent", "deeper"},
+				wantErrType: "*configuration.ConfigTypeMismatchError",
+				wantInError: `"nonexistent": test is string`,
+			},
+			teststruct{
+				name:        name + " through number",
+				get:         get,
+				path:        []string{"section", "value", "deeper"},
+				wantErrType: "*configuration.ConfigTypeMismatchError",
+				wantInError: `"deeper": section.value is float64`,
+			},
+			teststruct{
+				name:        name + " through array item",
+				get:         get,
+				path:        []string{"section", "list", "1", "deeper"},
+				wantErrType: "*configuration.ConfigTypeMismatchError",
+				wantInError: `"deeper": section.list.1 is string`,
+			},
+			teststruct{
+				name:        name + " absent key",
+				get:         get,
+				path:        []string{"section", "nothing", "deeper"},
+				wantErrType: "*configuration.ConfigItemNotFound",
+			},
+		)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.get(tt.path...)
+			if nil == err {
+				t.Fatalf("%s error = nil, want %s", tt.name, tt.wantErrType)
+			}
+			if tt.wantErrType != reflect.TypeOf(err).String() {
+				t.Errorf("%s error type = %v, wantErrType %v", tt.name, reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !strings.Contains(err.Error(), tt.wantInError) {
+				t.Errorf("%s error = %v, want it contains %v", tt.name, err, tt.wantInError)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_numberGetters(t *testing.T) {
+	data := map[string]interface{}{
+		"int":      42.0,
+		"negative": -7.0,
+		"fraction": 1.9,
+		"big":      1e20,
+		"maxint64": 9223372036854775807.0, // it's 2^63 as float64
+		"large":    9007199254740992.0,
+		"native":   5,
+		"string":   "5",
+	}
+	type teststruct struct {
+		name        string
+		strict      bool
+		get         string
+		path        string
+		want        interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "float", get: "float", path: "fraction", want: 1.9},
+		{name: "float from int", get: "float", path: "native", want: 5.0},
+		{name: "float from string", get: "float", path: "string", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "float absent", get: "float", path: "nothing", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+		{name: "int", get: "int", path: "int", want: 42},
+		{name: "int negative", get: "int", path: "negative", want: -7},
+		{name: "int truncated", get: "int", path: "fraction", want: 1},
+		{name: "int strict fraction", strict: true, get: "int", path: "fraction", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "int strict integer", strict: true, get: "int", path: "int", want: 42},
+		{name: "int overflow", get: "int", path: "big", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "int64", get: "int64", path: "large", want: int64(9007199254740992)},
+		{name: "int64 negative", get: "int64", path: "negative", want: int64(-7)},
+		{name: "int64 overflow", get: "int64", path: "big", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "int64 2^63 overflow", get: "int64", path: "maxint64", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "int64 strict fraction", strict: true, get: "int64", path: "fraction", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "int64 from string", get: "int64", path: "string", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "uint64", get: "uint64", path: "maxint64", want: uint64(1 << 63)},
+		{name: "uint64 truncated", get: "uint64", path: "fraction", want: uint64(1)},
+		{name: "uint64 negative", get: "uint64", path: "negative", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "uint64 overflow", get: "uint64", path: "big", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+		{name: "uint64 strict fraction", strict: true, get: "uint64", path: "fraction", wantErr: true, wantErrType: "*configuration.ConfigTypeMismatchError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &HJSONConfig{hjsonMap: data}
+			fl.SetStrictNumbers(tt.strict)
+			var got interface{}
+			var err error
+			switch tt.get {
+			case "float":
+				got, err = fl.GetFloatValue(tt.path)
+			case "int":
+				got, err = fl.GetIntValue(tt.path)
+			case "int64":
+				got, err = fl.GetInt64Value(tt.path)
+			case "uint64":
+				got, err = fl.GetUint64Value(tt.path)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig %s getter error = %v, wantErr %v", tt.get, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if "" != tt.wantErrType && tt.wantErrType != reflect.TypeOf(err).String() {
+					t.Errorf("HJSONConfig %s getter error type = %v, wantErrType %v", tt.get, reflect.TypeOf(err), tt.wantErrType)
+				}
+				// error must tell what is wrong with value
+				if "*configuration.ConfigTypeMismatchError" == tt.wantErrType && "string" != tt.path && !strings.HasPrefix(err.Error(), tt.path+": value ") {
+					t.Errorf("HJSONConfig %s getter error = %v, want path and value described", tt.get, err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HJSONConfig %s getter = %#v, want %#v", tt.get, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_numberGetters_boundaries(t *testing.T) {
+	load := func(format string, cnt string) IConfig {
+		var c IConfig
+		var err error
+		switch format {
+		case "hjson":
+			c, err = NewHJSONConfig([]byte(cnt))
+		case "yaml":
+			c, err = NewYAMLConfig([]byte(cnt))
+		case "toml":
+			c, err = NewTOMLConfig([]byte(cnt))
+		}
+		if err != nil {
+			t.Fatalf("%s config error = %v", format, err)
+		}
+		return c
+	}
+	configs := map[string]IConfig{
+		"hjson": load("hjson", "{max: 9223372036854775807, min: -9223372036854775808, umax: 18446744073709551615, exact: 9007199254740993}"),
+		"yaml":  load("yaml", "max: 9223372036854775807\nmin: -9223372036854775808\numax: 18446744073709551615\nexact: 9007199254740993\n"),
+		// TOML integers are int64 only
+		"toml": load("toml", "max = 9223372036854775807\nmin = -9223372036854775808\nexact = 9007199254740993\n"),
+	}
+	for format, c := range configs {
+		if v, err := c.GetInt64Value("max"); err != nil || v != math.MaxInt64 {
+			t.Errorf("%s GetInt64Value() for MaxInt64 = %v, %v", format, v, err)
+		}
+		if v, err := c.GetUint64Value("max"); err != nil || v != math.MaxInt64 {
+			t.Errorf("%s GetUint64Value() for MaxInt64 = %v, %v", format, v, err)
+		}
+		if v, err := c.GetInt64Value("min"); err != nil || v != math.MinInt64 {
+			t.Errorf("%s GetInt64Value() for MinInt64 = %v, %v", format, v, err)
+		}
+		if _, err := c.GetUint64Value("min"); err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+			t.Errorf("%s GetUint64Value() for MinInt64 error = %v, want ConfigTypeMismatchError", format, err)
+		}
+		if v, err := c.GetInt64Value("exact"); err != nil || v != 1<<53+1 {
+			t.Errorf("%s GetInt64Value() for 2^53+1 = %v, %v", format, v, err)
+		}
+		if v, err := c.GetUint64Value("exact"); err != nil || v != 1<<53+1 {
+			t.Errorf("%s GetUint64Value() for 2^53+1 = %v, %v", format, v, err)
+		}
+		if "toml" == format {
+			continue
+		}
+		if v, err := c.GetUint64Value("umax"); err != nil || v != math.MaxUint64 {
+			t.Errorf("%s GetUint64Value() for MaxUint64 = %v, %v", format, v, err)
+		}
+		if _, err := c.GetInt64Value("umax"); err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+			t.Errorf("%s GetInt64Value() for MaxUint64 error = %v, want ConfigTypeMismatchError", format, err)
+		}
+	}
+	// integers float64 keeps exactly are still float64 like HJSON gives them
+	if v, _ := configs["yaml"].GetValue("exact"); reflect.TypeOf(v).String() != "int64" {
+		t.Errorf("YAML GetValue() for 2^53+1 type = %T, want int64", v)
+	}
+	small, _ := NewYAMLConfig([]byte("a: 1\n"))
+	if v, _ := small.GetValue("a"); v != float64(1) {
+		t.Errorf("YAML GetValue() for small integer = %#v, want float64(1)", v)
+	}
+}
+
+func TestHJSONConfig_SetStrictNumbers_subconfig(t *testing.T) {
+	fl, err := NewHJSONConfig(map[string]interface{}{"section": map[string]interface{}{"value": 1.5}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.SetStrictNumbers(true)
+	sub, err := fl.GetSubconfig("section")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = sub.GetInt64Value("value"); nil == err || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+		t.Errorf("subconfig GetInt64Value() error = %v, want ConfigTypeMismatchError", err)
+	}
+	if _, err = fl.Snapshot().GetInt64Value("section", "value"); nil == err || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+		t.Errorf("snapshot GetInt64Value() error = %v, want ConfigTypeMismatchError", err)
+	}
+}
@@ -0,0 +1,126 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// INIConfig is configuration loader INI interface
+// sections become top level keys, so values are got by GetValue("section", "key")
+// keys placed before any section are top level values
+type INIConfig struct {
+	HJSONConfig
+}
+
+// iniParser parses INI contents for HJSONConfig internals
+type iniParser struct{}
+
+// ParseStringContents parses INI file contents
+func (p iniParser) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	m = map[string]interface{}{}
+	current := m
+	scanner := bufio.NewScanner(bytes.NewReader(cnt))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if "" == line || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, NewConfigParseError(fmt.Sprintf("INI line %d: section header is not closed", lineNo))
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if "" == name {
+				return nil, NewConfigParseError(fmt.Sprintf("INI line %d: empty section name", lineNo))
+			}
+			switch v := m[name].(type) {
+			case nil:
+				current = map[string]interface{}{}
+				m[name] = current
+			case map[string]interface{}:
+				// section is declared twice - merge them
+				current = v
+			default:
+				return nil, NewConfigParseError(fmt.Sprintf("INI line %d: section %q conflicts with top level key", lineNo, name))
+			}
+			continue
+		}
+		pos := strings.IndexAny(line, "=:")
+		if pos <= 0 {
+			return nil, NewConfigParseError(fmt.Sprintf("INI line %d: key = value expected", lineNo))
+		}
+		key := strings.TrimSpace(line[:pos])
+		val := strings.TrimSpace(line[pos+1:])
+		if _, ok := current[key].(map[string]interface{}); ok {
+			return nil, NewConfigParseError(fmt.Sprintf("INI line %d: key %q conflicts with section", lineNo, key))
+		}
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			// quoted values are always strings
+			if val[0] == '"' {
+				s, err := strconv.Unquote(val)
+				if err != nil {
+					return nil, NewConfigParseError(fmt.Sprintf("INI line %d: wrong quoted value: %v", lineNo, err))
+				}
+				current[key] = s
+			} else {
+				current[key] = val[1 : len(val)-1]
+			}
+			continue
+		}
+		current[key] = inferScalar(val)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// plainNumberRe matches decimal numbers only. strconv.ParseFloat accepts hex, inf and nan too
+var plainNumberRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// inferScalar makes typed value from text value of flat formats(INI, properties)
+// so values have the same types HJSON parser gives: bool, float64 or string.
+// Big integers are kept exact as parseNumber does
+func inferScalar(s string) interface{} {
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if plainNumberRe.MatchString(s) {
+		if n, err := parseNumber(s); err == nil {
+			return n
+		}
+	}
+	return s
+}
+
+// ParseStringContents parses INI - separated to method cause I want test that
+func (fl *INIConfig) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	return iniParser{}.ParseStringContents(cnt)
+}
+
+// SetDefaultLoadSetting sets default config file for loader
+// arguments are the same as HJSONConfig.SetDefaultLoadSetting has
+func (fl *INIConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	fl.setParser(iniParser{})
+	return fl.HJSONConfig.SetDefaultLoadSetting(sl...)
+}
+
+// NewINIConfig creates new object or gives error
+// all arguments are same as HJSONConfig.SetDefaultLoadSetting
+func NewINIConfig(sl ...interface{}) (fl *INIConfig, err error) {
+	fl = &INIConfig{}
+	err = fl.SetDefaultLoadSetting(sl...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
@@ -0,0 +1,104 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestINIConfig_ParseStringContents(t *testing.T) {
+	type args struct {
+		cnt []byte
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantM       map[string]interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name:        "not closed section",
+			args:        args{cnt: []byte("[section\na=1\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name:        "line without value",
+			args:        args{cnt: []byte("[section]\njustkey\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name:        "section conflicts with top level key",
+			args:        args{cnt: []byte("a=1\n[a]\nb=2\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name: "sections, comments and typed values",
+			args: args{cnt: []byte("; comment\ntop = x\n[s1]\n# comment\nnum = 42\nflag: TRUE\nquoted = \"42\"\nsingle = ' a '\nhex = 0x10\n[s2]\na=b=c\n[s1]\nmore = 1.5\n")},
+			wantM: map[string]interface{}{
+				"top": "x",
+				"s1": map[string]interface{}{
+					"num":    float64(42),
+					"flag":   true,
+					"quoted": "42",
+					"single": " a ",
+					"hex":    "0x10",
+					"more":   float64(1.5),
+				},
+				"s2": map[string]interface{}{"a": "b=c"},
+			},
+			wantErr:     false,
+			wantErrType: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &INIConfig{}
+			gotM, err := fl.ParseStringContents(tt.args.cnt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("INIConfig.ParseStringContents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("INIConfig.ParseStringContents() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if !reflect.DeepEqual(gotM, tt.wantM) {
+				t.Errorf("INIConfig.ParseStringContents() = %v, want %v", gotM, tt.wantM)
+			}
+		})
+	}
+}
+
+func TestNewINIConfig(t *testing.T) {
+	fl, err := NewINIConfig("./test.ini")
+	if err != nil {
+		t.Errorf("NewINIConfig() error = %v", err)
+		return
+	}
+	i, err := fl.GetIntValue("server", "port")
+	if err != nil || i != 8080 {
+		t.Errorf("INIConfig.GetIntValue() = %v, %v, want 8080", i, err)
+	}
+	_, err = fl.GetIntValue("server", "name")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+		t.Errorf("INIConfig.GetIntValue() on quoted value error = %v, want ConfigTypeMismatchError", err)
+	}
+	_, err = fl.GetValue("server", "nothing")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigItemNotFound" {
+		t.Errorf("INIConfig.GetValue() on absent key error = %v, want ConfigItemNotFound", err)
+	}
+	if err = fl.ReloadInternalMap(); err != nil {
+		t.Errorf("INIConfig.ReloadInternalMap() error = %v", err)
+	}
+	_, err = NewINIConfig([]byte("[broken"))
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigParseError" {
+		t.Errorf("NewINIConfig() error = %v, want ConfigParseError", err)
+	}
+}
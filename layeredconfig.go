@@ -0,0 +1,243 @@
+package configuration
+
+import "sync"
+
+// ConfigLayer is one source of LayeredConfig
+type ConfigLayer struct {
+	// Name is used by LayeredConfig.Source() to say where value came from
+	Name string
+	// Config is layer config. nil for environment layers made by EnvLayer
+	Config IConfig
+	// envPrefix and envSeparator are set for environment layers
+	envPrefix    string
+	envSeparator string
+}
+
+// EnvLayer makes layer of environment variables for LayeredConfig.
+// Variables are the same as NewEnvConfig takes, values are coerced to types of values of lower layers
+func EnvLayer(name string, prefix string, separator string) ConfigLayer {
+	if "" == separator {
+		separator = DefaultEnvSeparator
+	}
+	return ConfigLayer{Name: name, envPrefix: prefix, envSeparator: separator}
+}
+
+// LayeredConfig stacks several configs. Maps are merged by key and later layer wins,
+// e.g. defaults map, base file, environment file, local override file, environment variables.
+// Arrays are replaced by default. Other strategies are set by AddMergePolicy or by directives
+// in keys of higher layer: "allowed_hosts+" appends array, "+allowed_hosts" prepends it
+type LayeredConfig struct {
+	HJSONConfig
+	// update serializes layer changes and merges. Merged state is set under HJSONConfig.mutex
+	update sync.Mutex
+	// layers and policies are never changed in place: merge sets new slices
+	layers   []ConfigLayer
+	policies []MergePolicy
+	// layerMaps are values each layer gave on last merge
+	layerMaps []map[string]interface{}
+}
+
+// NewLayeredConfig makes config of layers given from lowest to highest priority
+func NewLayeredConfig(layers ...ConfigLayer) (fl *LayeredConfig, err error) {
+	fl = &LayeredConfig{}
+	for _, l := range layers {
+		if err = fl.checkLayer(l); err != nil {
+			return nil, err
+		}
+	}
+	if err = fl.merge(append([]ConfigLayer{}, layers...), nil); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+// checkLayer checks layer before it's added. Caller must hold update lock
+func (fl *LayeredConfig) checkLayer(l ConfigLayer) error {
+	if "" == l.Name {
+		return NewConfigUsageError("Layer name must not be empty")
+	}
+	if nil == l.Config && "" == l.envPrefix {
+		return NewConfigUsageError("Layer " + l.Name + " has no config")
+	}
+	for _, other := range fl.layers {
+		if other.Name == l.Name {
+			return NewConfigUsageError("Layer " + l.Name + " is already added")
+		}
+	}
+	return nil
+}
+
+// AddLayer adds layer with highest priority
+func (fl *LayeredConfig) AddLayer(l ConfigLayer) (err error) {
+	fl.update.Lock()
+	defer fl.update.Unlock()
+	if err = fl.checkLayer(l); err != nil {
+		return err
+	}
+	layers := make([]ConfigLayer, 0, len(fl.layers)+1)
+	layers = append(append(layers, fl.layers...), l)
+	return fl.merge(layers, fl.policies)
+}
+
+// AddMergePolicy adds array merge policy and merges layers again.
+// If several policies match path the first added one is used
+func (fl *LayeredConfig) AddMergePolicy(p MergePolicy) (err error) {
+	if err = checkMergePolicy(p); err != nil {
+		return err
+	}
+	fl.update.Lock()
+	defer fl.update.Unlock()
+	policies := make([]MergePolicy, 0, len(fl.policies)+1)
+	policies = append(append(policies, fl.policies...), p)
+	return fl.merge(fl.layers, policies)
+}
+
+// merge builds internal map from layers and policies given and sets them
+// only if merge succeeded. Caller must hold update lock except in constructor
+func (fl *LayeredConfig) merge(layers []ConfigLayer, policies []MergePolicy) error {
+	mr := &merger{policies: policies}
+	m := map[string]interface{}{}
+	layerMaps := make([]map[string]interface{}, len(layers))
+	for i, l := range layers {
+		if nil == l.Config {
+			env := &EnvConfig{base: &HJSONConfig{hjsonMap: m}, prefix: l.envPrefix, separator: l.envSeparator}
+			if err := env.apply(); err != nil {
+				return err
+			}
+			m = env.getMap()
+			// environment layer gives overridden values only
+			layerMaps[i] = map[string]interface{}{}
+			for _, o := range env.overrides {
+				setPathValue(layerMaps[i], o.Path, o.Value)
+			}
+			continue
+		}
+		lm, err := configMap(l.Config)
+		if err != nil {
+			return NewConfigUsageError("Layer " + l.Name + ": " + err.Error())
+		}
+		if err = mr.mergeMaps(m, lm, nil); err != nil {
+			return NewConfigTypeMismatchError("Layer " + l.Name + ": " + err.Error())
+		}
+		layerMaps[i] = lm
+	}
+	fl.mutex.Lock()
+	fl.filename = ""
+	fl.hjsonMap = m
+	fl.layers = layers
+	fl.policies = policies
+	fl.layerMaps = layerMaps
+	fl.mutex.Unlock()
+	return nil
+}
+
+// Source returns name of layer which supplied value by path.
+// For objects it's the highest layer having them. ConfigItemNotFound if no layer has the value
+func (fl *LayeredConfig) Source(path ...string) (name string, err error) {
+	if 0 == len(path) {
+		return "", NewConfigUsageError("You must get values with path arguments there")
+	}
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	for i := len(fl.layerMaps) - 1; i >= 0; i-- {
+		if hasPathValue(fl.layerMaps[i], path) {
+			return fl.layers[i].Name, nil
+		}
+	}
+	return "", NewConfigItemNotFound("Item not found")
+}
+
+// Layers returns names of layers from lowest to highest priority
+func (fl *LayeredConfig) Layers() []string {
+	layers := fl.getLayers()
+	names := make([]string, len(layers))
+	for i, l := range layers {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// SetDefaultLoadSetting is not supported by LayeredConfig, use AddLayer
+func (fl *LayeredConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	return NewConfigUsageError("LayeredConfig is set by layers, use AddLayer")
+}
+
+// CheckExternalConfig checks external configuration of all file backed layers
+func (fl *LayeredConfig) CheckExternalConfig() (err error) {
+	for _, l := range fl.getLayers() {
+		if nil == l.Config {
+			continue
+		}
+		if err = l.Config.CheckExternalConfig(); err != nil && !isUsageError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReloadInternalMap reloads all file backed layers and merges them again
+func (fl *LayeredConfig) ReloadInternalMap() (err error) {
+	fl.update.Lock()
+	defer fl.update.Unlock()
+	if err = fl.CheckExternalConfig(); err != nil {
+		return err
+	}
+	for _, l := range fl.layers {
+		if nil == l.Config {
+			continue
+		}
+		if err = l.Config.ReloadInternalMap(); err != nil && !isUsageError(err) {
+			return err
+		}
+	}
+	return fl.merge(fl.layers, fl.policies)
+}
+
+// getLayers gives current layers
+func (fl *LayeredConfig) getLayers() []ConfigLayer {
+	fl.mutex.RLock()
+	defer fl.mutex.RUnlock()
+	return fl.layers
+}
+
+// isUsageError says if err is ConfigUsageError e.g. config is not file backed
+func isUsageError(err error) bool {
+	_, ok := err.(*ConfigUsageError)
+	return ok
+}
+
+// setPathValue puts value to map by path creating absent objects
+func setPathValue(m map[string]interface{}, path []string, v interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = v
+}
+
+// hasPathValue says if map has value by exact path
+// keys with merge directives("hosts+": [...], "+hosts": [...]) supply values too
+func hasPathValue(m map[string]interface{}, path []string) bool {
+	var v interface{} = m
+	for _, key := range path {
+		current, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		found := false
+		for _, k := range []string{key, key + mergeDirective, mergeDirective + key} {
+			if v, found = current[k]; found && (k == key || isDirective(k, v)) {
+				break
+			}
+			found = false
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
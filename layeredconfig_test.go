@@ -0,0 +1,178 @@
+package configuration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestLayeredConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configuration-layered")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, cnt string) string {
+		p := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(p, []byte(cnt), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	defaults, _ := NewHJSONConfig(map[string]interface{}{
+		"server": map[string]interface{}{"host": "0.0.0.0", "port": float64(80), "timeout": float64(30)},
+		"debug":  false,
+	})
+	base, err := NewHJSONConfig(write("base.hjson", "{\n  server: {port: 8080}\n  name: base\n}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envFile, err := NewYAMLConfig(write("prod.yaml", "server:\n  host: prod.example.com\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	localPath := write("local.hjson", "{\n  name: local\n}")
+	local, err := NewHJSONConfig(localPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("TESTLAYERED_SERVER__TIMEOUT", "60")
+	defer os.Unsetenv("TESTLAYERED_SERVER__TIMEOUT")
+	fl, err := NewLayeredConfig(
+		ConfigLayer{Name: "defaults", Config: defaults},
+		ConfigLayer{Name: "base", Config: base},
+		ConfigLayer{Name: "env file", Config: envFile},
+		ConfigLayer{Name: "local", Config: local},
+		EnvLayer("environment", "TESTLAYERED_", ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type teststruct struct {
+		path       []string
+		wantValue  interface{}
+		wantSource string
+	}
+	tests := []teststruct{
+		{path: []string{"server", "host"}, wantValue: "prod.example.com", wantSource: "env file"},
+		{path: []string{"server", "port"}, wantValue: float64(8080), wantSource: "base"},
+		{path: []string{"server", "timeout"}, wantValue: float64(60), wantSource: "environment"},
+		{path: []string{"debug"}, wantValue: false, wantSource: "defaults"},
+		{path: []string{"name"}, wantValue: "local", wantSource: "local"},
+		{path: []string{"server"}, wantSource: "environment"},
+	}
+	for _, tt := range tests {
+		if nil != tt.wantValue {
+			v, err := fl.GetValue(tt.path...)
+			if err != nil || !reflect.DeepEqual(v, tt.wantValue) {
+				t.Errorf("LayeredConfig.GetValue(%v) = %v, %v, want %v", tt.path, v, err, tt.wantValue)
+			}
+		}
+		s, err := fl.Source(tt.path...)
+		if err != nil || s != tt.wantSource {
+			t.Errorf("LayeredConfig.Source(%v) = %v, %v, want %v", tt.path, s, err, tt.wantSource)
+		}
+	}
+	if _, err = fl.Source("nothing"); err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigItemNotFound" {
+		t.Errorf("LayeredConfig.Source() for absent value error = %v, want ConfigItemNotFound", err)
+	}
+	if _, err = fl.Source("name", "deeper"); err == nil {
+		t.Errorf("LayeredConfig.Source() through scalar error = nil")
+	}
+	// lower layers stay untouched
+	if v, _ := defaults.GetIntValue("server", "port"); v != 80 {
+		t.Errorf("defaults layer was changed by merge")
+	}
+
+	write("local.hjson", "{\n  name: reloaded\n  debug: true\n}")
+	if err = fl.ReloadInternalMap(); err != nil {
+		t.Errorf("LayeredConfig.ReloadInternalMap() error = %v", err)
+	}
+	if s, _ := fl.GetStringValue("name"); s != "reloaded" {
+		t.Errorf("LayeredConfig.GetStringValue() after reload = %v, want reloaded", s)
+	}
+	if s, _ := fl.Source("debug"); s != "local" {
+		t.Errorf("LayeredConfig.Source() after reload = %v, want local", s)
+	}
+	write("local.hjson", `{broken`)
+	if err = fl.ReloadInternalMap(); err == nil {
+		t.Errorf("LayeredConfig.ReloadInternalMap() with broken file error = nil")
+	}
+	if s, _ := fl.GetStringValue("name"); s != "reloaded" {
+		t.Errorf("LayeredConfig.GetStringValue() after failed reload = %v, want reloaded", s)
+	}
+
+	override, _ := NewHJSONConfig(map[string]interface{}{"name": "override"})
+	if err = fl.AddLayer(ConfigLayer{Name: "override", Config: override}); err != nil {
+		t.Errorf("LayeredConfig.AddLayer() error = %v", err)
+	}
+	if s, _ := fl.Source("name"); s != "override" {
+		t.Errorf("LayeredConfig.Source() after AddLayer = %v, want override", s)
+	}
+	if !reflect.DeepEqual(fl.Layers(), []string{"defaults", "base", "env file", "local", "environment", "override"}) {
+		t.Errorf("LayeredConfig.Layers() = %v", fl.Layers())
+	}
+	if err = fl.AddLayer(ConfigLayer{Name: "base", Config: override}); err == nil {
+		t.Errorf("LayeredConfig.AddLayer() with duplicate name error = nil")
+	}
+	if err = fl.AddLayer(ConfigLayer{Name: "empty"}); err == nil {
+		t.Errorf("LayeredConfig.AddLayer() without config error = nil")
+	}
+	if err = fl.SetDefaultLoadSetting("file.hjson"); err == nil {
+		t.Errorf("LayeredConfig.SetDefaultLoadSetting() error = nil")
+	}
+}
+
+func TestLayeredConfig_concurrent(t *testing.T) {
+	base, _ := NewHJSONConfig(map[string]interface{}{"hosts": []interface{}{"a"}})
+	fl, err := NewLayeredConfig(ConfigLayer{Name: "base", Config: base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			l, _ := NewHJSONConfig(map[string]interface{}{"hosts": []interface{}{fmt.Sprint(i)}})
+			if err := fl.AddLayer(ConfigLayer{Name: fmt.Sprint("layer", i), Config: l}); err != nil {
+				t.Errorf("LayeredConfig.AddLayer() error = %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := fl.AddMergePolicy(MergePolicy{Path: "hosts", Strategy: MergeAppend}); err != nil {
+				t.Errorf("LayeredConfig.AddMergePolicy() error = %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := fl.Source("hosts"); err != nil {
+				t.Errorf("LayeredConfig.Source() error = %v", err)
+				return
+			}
+			fl.Layers()
+			if err := fl.ReloadInternalMap(); err != nil {
+				t.Errorf("LayeredConfig.ReloadInternalMap() error = %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+	if n := len(fl.Layers()); n != 21 {
+		t.Errorf("LayeredConfig.Layers() count = %v, want 21", n)
+	}
+	if v, _ := fl.GetStringSlice("hosts"); len(v) != 21 {
+		t.Errorf("LayeredConfig.GetStringSlice() = %v, want all layers appended", v)
+	}
+}
@@ -0,0 +1,226 @@
+package configuration
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// MergeStrategy says how array of higher layer is combined with array of lower one
+type MergeStrategy int
+
+const (
+	// MergeReplace makes higher layer array replace lower one. It's default strategy
+	MergeReplace MergeStrategy = iota
+	// MergeAppend appends higher layer items to lower layer ones
+	MergeAppend
+	// MergePrepend puts higher layer items before lower layer ones
+	MergePrepend
+	// MergeByID merges objects with the same MergePolicy.IDField value, other items are appended
+	MergeByID
+)
+
+// mergeDirective at the end of key makes array be appended to lower layer one: "allowed_hosts+": [...]
+// at the beginning of key it makes array be prepended: "+allowed_hosts": [...]
+const mergeDirective = "+"
+
+// MergePolicy sets merge strategy for arrays matching path glob.
+// Path is dotted: "http.allowed_hosts". Path element "*" or other path.Match pattern
+// matches one element, "**" matches any count of elements: "**.hosts"
+type MergePolicy struct {
+	Path     string
+	Strategy MergeStrategy
+	// IDField is object field items are matched by for MergeByID strategy
+	IDField string
+}
+
+// matches says if policy path glob matches path
+func (p MergePolicy) matches(path []string) bool {
+	return matchGlob(strings.Split(p.Path, "."), path)
+}
+
+// matchGlob matches dotted glob elements with path elements
+func matchGlob(glob []string, p []string) bool {
+	if 0 == len(glob) {
+		return 0 == len(p)
+	}
+	if "**" == glob[0] {
+		for i := 0; i <= len(p); i++ {
+			if matchGlob(glob[1:], p[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if 0 == len(p) {
+		return false
+	}
+	if ok, err := path.Match(glob[0], p[0]); err != nil || !ok {
+		return false
+	}
+	return matchGlob(glob[1:], p[1:])
+}
+
+// checkMergePolicy checks policy before it's used
+func checkMergePolicy(p MergePolicy) error {
+	if "" == p.Path {
+		return NewConfigUsageError("Merge policy path must not be empty")
+	}
+	for _, g := range strings.Split(p.Path, ".") {
+		if _, err := path.Match(g, ""); err != nil {
+			return NewConfigUsageError("Wrong merge policy path " + p.Path + ": " + err.Error())
+		}
+	}
+	if p.Strategy < MergeReplace || p.Strategy > MergeByID {
+		return NewConfigUsageError(fmt.Sprintf("Unknown merge strategy %d", p.Strategy))
+	}
+	if MergeByID == p.Strategy && "" == p.IDField {
+		return NewConfigUsageError("Merge policy " + p.Path + " needs IDField for MergeByID strategy")
+	}
+	return nil
+}
+
+// merger merges maps of layers using merge policies
+type merger struct {
+	policies []MergePolicy
+}
+
+// policyFor finds first policy matching path. MergeReplace if nothing matches
+func (mr *merger) policyFor(p []string) MergePolicy {
+	for _, policy := range mr.policies {
+		if policy.matches(p) {
+			return policy
+		}
+	}
+	return MergePolicy{Strategy: MergeReplace}
+}
+
+// isDirective says if key with value is merge directive. Directives are "+" at the beginning
+// or at the end of key with array value, other keys like "c++": "yes" are plain ones
+func isDirective(k string, v interface{}) bool {
+	if len(k) < 2 || !(strings.HasSuffix(k, mergeDirective) || strings.HasPrefix(k, mergeDirective)) {
+		return false
+	}
+	_, ok := v.([]interface{})
+	return ok
+}
+
+// mergeMaps merges src into dst recursively: maps are merged by key and src values win.
+// arrays are combined by directives in src keys or by merge policies.
+// dst must be owned by caller, src is not changed
+func (mr *merger) mergeMaps(dst, src map[string]interface{}, p []string) error {
+	plain := []string{}
+	directives := []string{}
+	for k, v := range src {
+		if isDirective(k, v) {
+			directives = append(directives, k)
+		} else {
+			plain = append(plain, k)
+		}
+	}
+	sort.Strings(plain)
+	sort.Strings(directives)
+	for _, k := range plain {
+		kp := appendPath(p, k)
+		v := src[k]
+		switch sv := v.(type) {
+		case map[string]interface{}:
+			// new subtrees are merged into empty map too so their directives are resolved
+			dm, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dm = map[string]interface{}{}
+			}
+			if err := mr.mergeMaps(dm, sv, kp); err != nil {
+				return err
+			}
+			dst[k] = dm
+			continue
+		case []interface{}:
+			if dl, ok := dst[k].([]interface{}); ok {
+				l, err := mr.mergeArrays(dl, sv, kp, mr.policyFor(kp))
+				if err != nil {
+					return err
+				}
+				dst[k] = l
+				continue
+			}
+		}
+		dst[k] = deepCopyValue(v)
+	}
+	// directives go after plain keys so "hosts" and "hosts+" in one layer give replace then append
+	for _, k := range directives {
+		if strings.HasSuffix(k, mergeDirective) && strings.HasPrefix(k, mergeDirective) {
+			return NewConfigUsageError("Key " + pathString(appendPath(p, k)) + " has both append and prepend directives")
+		}
+		policy := MergePolicy{Strategy: MergeAppend}
+		key := strings.TrimSuffix(k, mergeDirective)
+		if key == k {
+			policy.Strategy = MergePrepend
+			key = strings.TrimPrefix(k, mergeDirective)
+		}
+		kp := appendPath(p, key)
+		sv := src[k].([]interface{})
+		dl, _ := dst[key].([]interface{})
+		l, err := mr.mergeArrays(dl, sv, kp, policy)
+		if err != nil {
+			return err
+		}
+		dst[key] = l
+	}
+	return nil
+}
+
+// mergeArrays combines arrays by policy strategy. result does not share memory with src
+func (mr *merger) mergeArrays(dst, src []interface{}, p []string, policy MergePolicy) ([]interface{}, error) {
+	switch policy.Strategy {
+	case MergeAppend:
+		l := make([]interface{}, 0, len(dst)+len(src))
+		l = append(l, dst...)
+		return append(l, deepCopyValue(src).([]interface{})...), nil
+	case MergePrepend:
+		l := make([]interface{}, 0, len(dst)+len(src))
+		l = append(l, deepCopyValue(src).([]interface{})...)
+		return append(l, dst...), nil
+	case MergeByID:
+		l := make([]interface{}, len(dst), len(dst)+len(src))
+		copy(l, dst)
+		for _, item := range src {
+			sm, ok := item.(map[string]interface{})
+			id, hasID := sm[policy.IDField]
+			if !ok || !hasID || !isScalarID(id) {
+				l = append(l, deepCopyValue(item))
+				continue
+			}
+			merged := false
+			for i, existing := range l {
+				dm, ok := existing.(map[string]interface{})
+				if !ok || dm[policy.IDField] != id {
+					continue
+				}
+				if err := mr.mergeMaps(dm, sm, appendPath(p, fmt.Sprint(id))); err != nil {
+					return nil, err
+				}
+				l[i] = dm
+				merged = true
+				break
+			}
+			if !merged {
+				l = append(l, deepCopyValue(item))
+			}
+		}
+		return l, nil
+	default:
+		return deepCopyValue(src).([]interface{}), nil
+	}
+}
+
+// isScalarID says if value may be used as MergeByID id. maps and arrays are not comparable
+func isScalarID(v interface{}) bool {
+	switch v.(type) {
+	case string, float64, int64, uint64, bool, int:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,215 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerger_mergeMaps(t *testing.T) {
+	type teststruct struct {
+		name        string
+		policies    []MergePolicy
+		dst         map[string]interface{}
+		src         map[string]interface{}
+		want        map[string]interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name: "maps are merged and arrays are replaced by default",
+			dst: map[string]interface{}{
+				"a":     map[string]interface{}{"b": float64(1), "c": float64(2)},
+				"hosts": []interface{}{"a", "b"},
+			},
+			src: map[string]interface{}{
+				"a":     map[string]interface{}{"b": float64(3)},
+				"hosts": []interface{}{"c"},
+			},
+			want: map[string]interface{}{
+				"a":     map[string]interface{}{"b": float64(3), "c": float64(2)},
+				"hosts": []interface{}{"c"},
+			},
+		},
+		{
+			name:     "append policy by exact path",
+			policies: []MergePolicy{{Path: "http.allowed_hosts", Strategy: MergeAppend}},
+			dst:      map[string]interface{}{"http": map[string]interface{}{"allowed_hosts": []interface{}{"a"}, "other": []interface{}{"x"}}},
+			src:      map[string]interface{}{"http": map[string]interface{}{"allowed_hosts": []interface{}{"b"}, "other": []interface{}{"y"}}},
+			want:     map[string]interface{}{"http": map[string]interface{}{"allowed_hosts": []interface{}{"a", "b"}, "other": []interface{}{"y"}}},
+		},
+		{
+			name: "first matching policy wins, single element glob",
+			policies: []MergePolicy{
+				{Path: "*.hosts", Strategy: MergePrepend},
+				{Path: "**", Strategy: MergeAppend},
+			},
+			dst:  map[string]interface{}{"s": map[string]interface{}{"hosts": []interface{}{"a"}}, "top": []interface{}{"x"}},
+			src:  map[string]interface{}{"s": map[string]interface{}{"hosts": []interface{}{"b"}}, "top": []interface{}{"y"}},
+			want: map[string]interface{}{"s": map[string]interface{}{"hosts": []interface{}{"b", "a"}}, "top": []interface{}{"x", "y"}},
+		},
+		{
+			name:     "recursive glob matches any depth",
+			policies: []MergePolicy{{Path: "**.hosts", Strategy: MergeAppend}},
+			dst:      map[string]interface{}{"hosts": []interface{}{"a"}, "x": map[string]interface{}{"y": map[string]interface{}{"hosts": []interface{}{"c"}}}},
+			src:      map[string]interface{}{"hosts": []interface{}{"b"}, "x": map[string]interface{}{"y": map[string]interface{}{"hosts": []interface{}{"d"}}}},
+			want:     map[string]interface{}{"hosts": []interface{}{"a", "b"}, "x": map[string]interface{}{"y": map[string]interface{}{"hosts": []interface{}{"c", "d"}}}},
+		},
+		{
+			name:     "merge by id field",
+			policies: []MergePolicy{{Path: "backends", Strategy: MergeByID, IDField: "name"}},
+			dst: map[string]interface{}{"backends": []interface{}{
+				map[string]interface{}{"name": "a", "host": "a1", "port": float64(1)},
+				map[string]interface{}{"name": "b", "host": "b1"},
+			}},
+			src: map[string]interface{}{"backends": []interface{}{
+				map[string]interface{}{"name": "b", "host": "b2"},
+				map[string]interface{}{"name": "c", "host": "c1"},
+				map[string]interface{}{"host": "no id"},
+				map[string]interface{}{"name": []interface{}{"not comparable"}},
+			}},
+			want: map[string]interface{}{"backends": []interface{}{
+				map[string]interface{}{"name": "a", "host": "a1", "port": float64(1)},
+				map[string]interface{}{"name": "b", "host": "b2"},
+				map[string]interface{}{"name": "c", "host": "c1"},
+				map[string]interface{}{"host": "no id"},
+				map[string]interface{}{"name": []interface{}{"not comparable"}},
+			}},
+		},
+		{
+			name:     "in-file directives win over policies",
+			policies: []MergePolicy{{Path: "**", Strategy: MergeReplace}},
+			dst:      map[string]interface{}{"hosts": []interface{}{"a"}, "ports": []interface{}{float64(1)}},
+			src:      map[string]interface{}{"hosts+": []interface{}{"b"}, "+ports": []interface{}{float64(0)}, "new+": []interface{}{"n"}},
+			want: map[string]interface{}{
+				"hosts": []interface{}{"a", "b"},
+				"ports": []interface{}{float64(0), float64(1)},
+				"new":   []interface{}{"n"},
+			},
+		},
+		{
+			name: "plain key goes before directive in one layer",
+			dst:  map[string]interface{}{"hosts": []interface{}{"a"}},
+			src:  map[string]interface{}{"hosts+": []interface{}{"c"}, "hosts": []interface{}{"b"}},
+			want: map[string]interface{}{"hosts": []interface{}{"b", "c"}},
+		},
+		{
+			name: "directives in new subtree are resolved",
+			dst:  map[string]interface{}{"x": float64(1)},
+			src:  map[string]interface{}{"http": map[string]interface{}{"hosts+": []interface{}{"a"}, "tls": map[string]interface{}{"+ciphers": []interface{}{"c"}}}},
+			want: map[string]interface{}{
+				"x":    float64(1),
+				"http": map[string]interface{}{"hosts": []interface{}{"a"}, "tls": map[string]interface{}{"ciphers": []interface{}{"c"}}},
+			},
+		},
+		{
+			name: "keys with plus and not array value are plain keys",
+			dst:  map[string]interface{}{"langs": map[string]interface{}{"c++": "no"}},
+			src:  map[string]interface{}{"langs": map[string]interface{}{"c++": "yes", "+x": float64(1)}},
+			want: map[string]interface{}{"langs": map[string]interface{}{"c++": "yes", "+x": float64(1)}},
+		},
+		{
+			name:        "both directives",
+			dst:         map[string]interface{}{},
+			src:         map[string]interface{}{"+hosts+": []interface{}{}},
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcCopy := deepCopyValue(tt.src)
+			// results must be the same every time
+			for i := 0; i < 3; i++ {
+				dst := deepCopyValue(tt.dst).(map[string]interface{})
+				mr := &merger{policies: tt.policies}
+				err := mr.mergeMaps(dst, tt.src, nil)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("merger.mergeMaps() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.wantErr {
+					if tt.wantErrType != reflect.TypeOf(err).String() {
+						t.Errorf("merger.mergeMaps() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+					}
+					return
+				}
+				if !reflect.DeepEqual(dst, tt.want) {
+					t.Errorf("merger.mergeMaps() = %v, want %v", dst, tt.want)
+					return
+				}
+			}
+			if !reflect.DeepEqual(srcCopy, tt.src) {
+				t.Errorf("merger.mergeMaps() changed src")
+			}
+		})
+	}
+}
+
+func TestLayeredConfig_AddMergePolicy(t *testing.T) {
+	base, _ := NewHJSONConfig(map[string]interface{}{"allowed_hosts": []interface{}{"a"}, "ports": []interface{}{float64(1)}})
+	local, _ := NewHJSONConfig(map[string]interface{}{"allowed_hosts": []interface{}{"b"}, "+ports": []interface{}{float64(0)}})
+	fl, err := NewLayeredConfig(ConfigLayer{Name: "base", Config: base}, ConfigLayer{Name: "local", Config: local})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := fl.GetValue("allowed_hosts"); !reflect.DeepEqual(v, []interface{}{"b"}) {
+		t.Errorf("LayeredConfig.GetValue() = %v, want [b]", v)
+	}
+	if v, _ := fl.GetValue("ports"); !reflect.DeepEqual(v, []interface{}{float64(0), float64(1)}) {
+		t.Errorf("LayeredConfig.GetValue() = %v, want [0 1]", v)
+	}
+	if s, _ := fl.Source("ports"); s != "local" {
+		t.Errorf("LayeredConfig.Source() for directive key = %v, want local", s)
+	}
+	if err = fl.AddMergePolicy(MergePolicy{Path: "allowed_hosts", Strategy: MergeAppend}); err != nil {
+		t.Errorf("LayeredConfig.AddMergePolicy() error = %v", err)
+	}
+	if v, _ := fl.GetValue("allowed_hosts"); !reflect.DeepEqual(v, []interface{}{"a", "b"}) {
+		t.Errorf("LayeredConfig.GetValue() after AddMergePolicy() = %v, want [a b]", v)
+	}
+	a, _ := NewHJSONConfig(map[string]interface{}{"x": float64(1), "db": map[string]interface{}{"replicas+": []interface{}{"r1"}}})
+	b, _ := NewHJSONConfig(map[string]interface{}{"http": map[string]interface{}{"hosts+": []interface{}{"a"}}})
+	nested, err := NewLayeredConfig(ConfigLayer{Name: "a", Config: a}, ConfigLayer{Name: "b", Config: b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := nested.GetValue("http", "hosts"); err != nil || !reflect.DeepEqual(v, []interface{}{"a"}) {
+		t.Errorf("LayeredConfig.GetValue() for directive in new subtree = %v, %v, want [a]", v, err)
+	}
+	if s, _ := nested.Source("http", "hosts"); s != "b" {
+		t.Errorf("LayeredConfig.Source() for directive in new subtree = %v, want b", s)
+	}
+	if v, err := nested.GetValue("db", "replicas"); err != nil || !reflect.DeepEqual(v, []interface{}{"r1"}) {
+		t.Errorf("LayeredConfig.GetValue() for directive in first layer = %v, %v, want [r1]", v, err)
+	}
+	langsBase, _ := NewHJSONConfig(map[string]interface{}{"langs": map[string]interface{}{"c++": "yes"}})
+	langsLocal, _ := NewHJSONConfig(map[string]interface{}{"langs": map[string]interface{}{"go+": "yes", "c++": "no"}})
+	langs, err := NewLayeredConfig(ConfigLayer{Name: "base", Config: langsBase})
+	if err != nil {
+		t.Fatalf("NewLayeredConfig() with plus in plain key error = %v", err)
+	}
+	if err = langs.AddLayer(ConfigLayer{Name: "local", Config: langsLocal}); err != nil {
+		t.Fatalf("LayeredConfig.AddLayer() with plus in plain key error = %v", err)
+	}
+	if v, _ := langs.GetStringValue("langs", "c++"); v != "no" {
+		t.Errorf("LayeredConfig.GetStringValue() for plus in plain key = %v, want no", v)
+	}
+	if v, _ := langs.GetStringValue("langs", "go+"); v != "yes" {
+		t.Errorf("LayeredConfig.GetStringValue() for plus in plain key = %v, want yes", v)
+	}
+	if _, err = langs.Source("langs", "go"); err == nil {
+		t.Errorf("LayeredConfig.Source() for plain key with plus gave no error")
+	}
+	wrong := []MergePolicy{
+		{Path: "", Strategy: MergeAppend},
+		{Path: "a.[", Strategy: MergeAppend},
+		{Path: "a", Strategy: MergeStrategy(42)},
+		{Path: "a", Strategy: MergeByID},
+	}
+	for _, p := range wrong {
+		if err = fl.AddMergePolicy(p); err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+			t.Errorf("LayeredConfig.AddMergePolicy(%v) error = %v, want ConfigUsageError", p, err)
+		}
+	}
+}
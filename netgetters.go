@@ -0,0 +1,85 @@
+package configuration
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// stringOf gets string value by path for parsers below
+func (fl *HJSONConfig) stringOf(path []string, what string) (string, error) {
+	i1, err := fl.GetValue(path...)
+	if nil != err {
+		return "", err
+	}
+	s, ok := i1.(string)
+	if !ok {
+		return "", NewConfigTypeMismatchError(fmt.Sprintf("%s: expected %s string, got %T", pathString(path), what, i1))
+	}
+	return s, nil
+}
+
+// GetURLValue returns absolute URL with host by path: "https://upstream.local:8443/api"
+func (fl *HJSONConfig) GetURLValue(path ...string) (u *url.URL, err error) {
+	s, err := fl.stringOf(path, "URL")
+	if nil != err {
+		return nil, err
+	}
+	u, err = url.Parse(s)
+	if nil != err {
+		return nil, NewConfigTypeMismatchError(pathString(path) + ": " + err.Error())
+	}
+	if "" == u.Scheme {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: URL %q is not absolute", pathString(path), s))
+	}
+	// "localhost:8080" is parsed as scheme localhost with opaque part, "http://" has no host
+	if "" != u.Opaque || "" == u.Host {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: URL %q has no host", pathString(path), s))
+	}
+	return u, nil
+}
+
+// GetIPValue returns IPv4 or IPv6 address by path
+func (fl *HJSONConfig) GetIPValue(path ...string) (ip net.IP, err error) {
+	s, err := fl.stringOf(path, "IP")
+	if nil != err {
+		return nil, err
+	}
+	ip = net.ParseIP(s)
+	if nil == ip {
+		return nil, NewConfigTypeMismatchError(fmt.Sprintf("%s: invalid IP address %q", pathString(path), s))
+	}
+	return ip, nil
+}
+
+// GetCIDRValue returns network by path: "10.0.0.0/8", "fd00::/8"
+func (fl *HJSONConfig) GetCIDRValue(path ...string) (n *net.IPNet, err error) {
+	s, err := fl.stringOf(path, "CIDR")
+	if nil != err {
+		return nil, err
+	}
+	_, n, err = net.ParseCIDR(s)
+	if nil != err {
+		return nil, NewConfigTypeMismatchError(pathString(path) + ": " + err.Error())
+	}
+	return n, nil
+}
+
+// GetHostPortValue returns host and port of address by path: ":8080", "localhost:80", "[::1]:443".
+// Port must be number from 0 to 65535
+func (fl *HJSONConfig) GetHostPortValue(path ...string) (host string, port int, err error) {
+	s, err := fl.stringOf(path, "host:port")
+	if nil != err {
+		return "", 0, err
+	}
+	host, p, err := net.SplitHostPort(s)
+	if nil != err {
+		return "", 0, NewConfigTypeMismatchError(pathString(path) + ": " + err.Error())
+	}
+	port, err = strconv.Atoi(p)
+	if nil != err || port < 0 || port > 65535 {
+		return "", 0, NewConfigTypeMismatchError(fmt.Sprintf("%s: invalid port %q in address %q", pathString(path), p, s))
+	}
+	return host, port, nil
+}
@@ -0,0 +1,109 @@
+package configuration
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHJSONConfig_netGetters(t *testing.T) {
+	fl := &HJSONConfig{hjsonMap: map[string]interface{}{
+		"upstream":  "https://upstream.local:8443/api?x=1",
+		"relative":  "/api",
+		"badurl":    "http://[::1",
+		"nohost":    "http://",
+		"opaque":    "localhost:8080",
+		"ipv4":      "10.1.2.3",
+		"ipv6":      "fd00::1",
+		"badip":     "10.1.2",
+		"cidr":      "10.0.0.0/8",
+		"cidr6":     "fd00::/8",
+		"badcidr":   "10.0.0.0/33",
+		"listen":    ":8080",
+		"hostport":  "localhost:80",
+		"hostport6": "[::1]:443",
+		"noport":    "localhost",
+		"bigport":   "localhost:70000",
+		"nameport":  "localhost:http",
+		"number":    8080.0,
+	}}
+	type teststruct struct {
+		name        string
+		get         string
+		path        string
+		want        interface{}
+		wantErr     bool
+		wantErrType string
+		wantInError string
+	}
+	mismatch := "*configuration.ConfigTypeMismatchError"
+	tests := []teststruct{
+		{name: "url", get: "url", path: "upstream", want: "https://upstream.local:8443/api?x=1"},
+		{name: "url relative", get: "url", path: "relative", wantErr: true, wantErrType: mismatch, wantInError: "relative: URL \"/api\" is not absolute"},
+		{name: "url without host", get: "url", path: "nohost", wantErr: true, wantErrType: mismatch, wantInError: "nohost: URL \"http://\" has no host"},
+		{name: "url host and port only", get: "url", path: "opaque", wantErr: true, wantErrType: mismatch, wantInError: "opaque: URL \"localhost:8080\" has no host"},
+		{name: "url malformed", get: "url", path: "badurl", wantErr: true, wantErrType: mismatch, wantInError: "badurl: parse"},
+		{name: "url not string", get: "url", path: "number", wantErr: true, wantErrType: mismatch, wantInError: "number: expected URL string"},
+		{name: "url absent", get: "url", path: "nothing", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+		{name: "ipv4", get: "ip", path: "ipv4", want: "10.1.2.3"},
+		{name: "ipv6", get: "ip", path: "ipv6", want: "fd00::1"},
+		{name: "ip malformed", get: "ip", path: "badip", wantErr: true, wantErrType: mismatch, wantInError: `badip: invalid IP address "10.1.2"`},
+		{name: "cidr", get: "cidr", path: "cidr", want: "10.0.0.0/8"},
+		{name: "cidr6", get: "cidr", path: "cidr6", want: "fd00::/8"},
+		{name: "cidr malformed", get: "cidr", path: "badcidr", wantErr: true, wantErrType: mismatch, wantInError: "badcidr: invalid CIDR address"},
+		{name: "cidr of ip", get: "cidr", path: "ipv4", wantErr: true, wantErrType: mismatch, wantInError: "ipv4: invalid CIDR address"},
+		{name: "listen address", get: "hostport", path: "listen", want: ":8080"},
+		{name: "host and port", get: "hostport", path: "hostport", want: "localhost:80"},
+		{name: "ipv6 host and port", get: "hostport", path: "hostport6", want: "::1:443"},
+		{name: "no port", get: "hostport", path: "noport", wantErr: true, wantErrType: mismatch, wantInError: "noport: address localhost: missing port"},
+		{name: "port out of range", get: "hostport", path: "bigport", wantErr: true, wantErrType: mismatch, wantInError: `bigport: invalid port "70000"`},
+		{name: "port name", get: "hostport", path: "nameport", wantErr: true, wantErrType: mismatch, wantInError: `nameport: invalid port "http"`},
+		{name: "host and port not string", get: "hostport", path: "number", wantErr: true, wantErrType: mismatch, wantInError: "number: expected host:port string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var err error
+			switch tt.get {
+			case "url":
+				u, e := fl.GetURLValue(tt.path)
+				if nil == e {
+					got = u.String()
+				}
+				err = e
+			case "ip":
+				ip, e := fl.GetIPValue(tt.path)
+				got, err = ip.String(), e
+			case "cidr":
+				n, e := fl.GetCIDRValue(tt.path)
+				if nil == e {
+					got = n.String()
+				}
+				err = e
+			case "hostport":
+				host, port, e := fl.GetHostPortValue(tt.path)
+				if nil == e {
+					got = host + ":" + strconv.Itoa(port)
+				}
+				err = e
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig %s getter error = %v, wantErr %v", tt.get, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if tt.wantErrType != reflect.TypeOf(err).String() {
+					t.Errorf("HJSONConfig %s getter error type = %v, wantErrType %v", tt.get, reflect.TypeOf(err), tt.wantErrType)
+				}
+				if !strings.Contains(err.Error(), tt.wantInError) {
+					t.Errorf("HJSONConfig %s getter error = %v, want it contains %v", tt.get, err, tt.wantInError)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HJSONConfig %s getter = %v, want %v", tt.get, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package configuration
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+In this file we keep helpers for configs built on top of other configs(overlays and layers)
+*/
+
+// configMap returns whole map of config. Maps inside must not be changed by caller
+func configMap(c IConfig) (m map[string]interface{}, err error) {
+	if fl, ok := c.(interface{ internalMap() map[string]interface{} }); ok {
+		m = fl.internalMap()
+		if nil == m {
+			return nil, NewConfigUsageError("No config was initialized yet")
+		}
+		return m, nil
+	}
+	err = c.Unmarshal(&m)
+	return m, err
+}
+
+// internalMap gives configs built on HJSONConfig internals to overlays without copying
+func (fl *HJSONConfig) internalMap() map[string]interface{} {
+	return fl.getMap()
+}
+
+// deepCopyValue copies maps and arrays of config value recursively
+func deepCopyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = deepCopyValue(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, val := range t {
+			l[i] = deepCopyValue(val)
+		}
+		return l
+	default:
+		return v
+	}
+}
+
+// findKey finds key of map matching name case insensitively. exact match wins
+func findKey(m map[string]interface{}, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// overrideValue puts raw text value to map by path creating absent objects.
+// path elements are matched with existing keys case insensitively.
+// raw value is coerced to type of value it overrides.
+// returns real path and value put
+func overrideValue(m map[string]interface{}, path []string, raw string) ([]string, interface{}, error) {
+	realPath := make([]string, 0, len(path))
+	current := m
+	for i, name := range path {
+		key, ok := findKey(current, name)
+		if !ok {
+			key = strings.ToLower(name)
+		}
+		realPath = append(realPath, key)
+		if i == len(path)-1 {
+			v, err := coerceLike(current[key], raw)
+			if err != nil {
+				return realPath, nil, NewConfigTypeMismatchError(pathString(realPath) + ": " + err.Error())
+			}
+			current[key] = v
+			return realPath, v, nil
+		}
+		switch v := current[key].(type) {
+		case nil:
+			next := map[string]interface{}{}
+			current[key] = next
+			current = next
+		case map[string]interface{}:
+			current = v
+		default:
+			return realPath, nil, NewConfigTypeMismatchError(pathString(realPath) + ": value is not an object and can not have nested keys")
+		}
+	}
+	return realPath, nil, NewConfigUsageError("Empty override path")
+}
+
+// coerceLike converts raw text value to type of old value.
+// arrays take comma separated items, absent values get type by inferScalar
+func coerceLike(old interface{}, raw string) (interface{}, error) {
+	switch o := old.(type) {
+	case nil:
+		return inferScalar(raw), nil
+	case string:
+		return raw, nil
+	case float64, int64, uint64:
+		n, err := parseNumber(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, NewConfigTypeMismatchError("value " + strconv.Quote(raw) + " is not a number")
+		}
+		return n, nil
+	case bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, NewConfigTypeMismatchError("value " + strconv.Quote(raw) + " is not a boolean")
+		}
+		return b, nil
+	case []interface{}:
+		l := []interface{}{}
+		if "" == strings.TrimSpace(raw) {
+			return l, nil
+		}
+		var sample interface{}
+		if len(o) > 0 {
+			sample = o[0]
+		}
+		if _, ok := sample.(string); ok || nil == sample {
+			sample = ""
+		}
+		for _, item := range strings.Split(raw, ",") {
+			v, err := coerceLike(sample, strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, v)
+		}
+		return l, nil
+	case map[string]interface{}:
+		return nil, NewConfigTypeMismatchError("object can not be overridden by value " + strconv.Quote(raw))
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,79 @@
+package configuration
+
+import (
+	"strings"
+)
+
+// SplitPath splits dotted path like "section1.subsection2.value" into GetValue path elements.
+// `\.` is dot inside key and `\\` is backslash: "hosts.example\.com.port" gives
+// []string{"hosts", "example.com", "port"}. Other escapes are ConfigUsageError
+func SplitPath(path string) ([]string, error) {
+	res := []string{}
+	var key strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch c {
+		case '\\':
+			if i+1 >= len(path) || ('.' != path[i+1] && '\\' != path[i+1]) {
+				return nil, NewConfigUsageError("Wrong escape in path " + path + ": only \\. and \\\\ are allowed")
+			}
+			i++
+			key.WriteByte(path[i])
+		case '.':
+			res = append(res, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(c)
+		}
+	}
+	return append(res, key.String()), nil
+}
+
+// ParseJSONPointer splits RFC 6901 JSON Pointer like "/section1/subsection2/value" into GetValue path elements.
+// "~1" is slash inside key and "~0" is tilde. Empty pointer means whole document and gives empty path
+func ParseJSONPointer(pointer string) ([]string, error) {
+	if "" == pointer {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, NewConfigUsageError("JSON Pointer " + pointer + " must start with /")
+	}
+	res := strings.Split(pointer[1:], "/")
+	for i, token := range res {
+		for j := 0; j < len(token); j++ {
+			if '~' == token[j] && (j+1 >= len(token) || ('0' != token[j+1] && '1' != token[j+1])) {
+				return nil, NewConfigUsageError("Wrong escape in JSON Pointer " + pointer + ": only ~0 and ~1 are allowed")
+			}
+		}
+		// ~1 goes first so "~01" becomes "~1" and not "/"
+		res[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return res, nil
+}
+
+// GetValueByPath gets value by dotted path: fl.GetValueByPath("section1.subsection2.value")
+// see SplitPath for escaping rules. Array items are addressed by index: "servers.2.host"
+func (fl *HJSONConfig) GetValueByPath(path string) (i interface{}, err error) {
+	p, err := SplitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return fl.GetValue(p...)
+}
+
+// GetValueByPointer gets value by RFC 6901 JSON Pointer: fl.GetValueByPointer("/section1/subsection2/value")
+// Empty pointer gives copy of whole document, so changing it does not change config
+func (fl *HJSONConfig) GetValueByPointer(pointer string) (i interface{}, err error) {
+	p, err := ParseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if 0 == len(p) {
+		m := fl.getMap()
+		if nil == m {
+			return nil, NewConfigUsageError("No config was initialized yet")
+		}
+		return deepCopyValue(m), nil
+	}
+	return fl.GetValue(p...)
+}
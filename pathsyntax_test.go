@@ -0,0 +1,143 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	type teststruct struct {
+		name        string
+		path        string
+		want        []string
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "one key", path: "test", want: []string{"test"}},
+		{name: "dotted", path: "section1.subsection2.value", want: []string{"section1", "subsection2", "value"}},
+		{name: "escaped dot", path: `hosts.example\.com.port`, want: []string{"hosts", "example.com", "port"}},
+		{name: "escaped backslash", path: `a\\.b`, want: []string{`a\`, "b"}},
+		{name: "empty key", path: "a..b", want: []string{"a", "", "b"}},
+		{name: "wrong escape", path: `a\b`, wantErr: true, wantErrType: "*configuration.ConfigUsageError"},
+		{name: "trailing backslash", path: `a\`, wantErr: true, wantErrType: "*configuration.ConfigUsageError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitPath() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("SplitPath() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPath() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	type teststruct struct {
+		name        string
+		pointer     string
+		want        []string
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "whole document", pointer: "", want: []string{}},
+		{name: "empty key", pointer: "/", want: []string{""}},
+		{name: "path", pointer: "/section1/subsection2/value", want: []string{"section1", "subsection2", "value"}},
+		{name: "escaped slash", pointer: "/paths/~1api~1v1", want: []string{"paths", "/api/v1"}},
+		{name: "escaped tilde", pointer: "/m~0n", want: []string{"m~n"}},
+		{name: "escape order", pointer: "/~01", want: []string{"~1"}},
+		{name: "dots are keys", pointer: "/a.b", want: []string{"a.b"}},
+		{name: "no leading slash", pointer: "a/b", wantErr: true, wantErrType: "*configuration.ConfigUsageError"},
+		{name: "wrong escape", pointer: "/a~2", wantErr: true, wantErrType: "*configuration.ConfigUsageError"},
+		{name: "trailing tilde", pointer: "/a~", wantErr: true, wantErrType: "*configuration.ConfigUsageError"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSONPointer(tt.pointer)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseJSONPointer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("ParseJSONPointer() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseJSONPointer() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_GetValueByPath(t *testing.T) {
+	fl, err := NewHJSONConfig(map[string]interface{}{
+		"section1": map[string]interface{}{
+			"subsection2": map[string]interface{}{"value": 5.0},
+		},
+		"hosts": map[string]interface{}{
+			"example.com": map[string]interface{}{"port": 8080.0},
+		},
+		"paths": map[string]interface{}{
+			"/api/v1": "api",
+		},
+		"servers": []interface{}{
+			map[string]interface{}{"host": "a"},
+			map[string]interface{}{"host": "b"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type teststruct struct {
+		name        string
+		get         func(string) (interface{}, error)
+		path        string
+		want        interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{name: "dotted", get: fl.GetValueByPath, path: "section1.subsection2.value", want: 5.0},
+		{name: "dotted escaped", get: fl.GetValueByPath, path: `hosts.example\.com.port`, want: 8080.0},
+		{name: "dotted array index", get: fl.GetValueByPath, path: "servers.-1.host", want: "b"},
+		{name: "dotted not found", get: fl.GetValueByPath, path: "section1.nothing", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+		{name: "dotted wrong escape", get: fl.GetValueByPath, path: `section1\x`, wantErr: true, wantErrType: "*configuration.ConfigUsageError"},
+		{name: "pointer", get: fl.GetValueByPointer, path: "/section1/subsection2/value", want: 5.0},
+		{name: "pointer key with dot", get: fl.GetValueByPointer, path: "/hosts/example.com/port", want: 8080.0},
+		{name: "pointer escaped slash", get: fl.GetValueByPointer, path: "/paths/~1api~1v1", want: "api"},
+		{name: "pointer array index", get: fl.GetValueByPointer, path: "/servers/0/host", want: "a"},
+		{name: "pointer whole document", get: fl.GetValueByPointer, path: "", want: fl.hjsonMap},
+		{name: "pointer not found", get: fl.GetValueByPointer, path: "/servers/2", wantErr: true, wantErrType: "*configuration.ConfigItemNotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("get(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("get(%q) error type = %v, wantErrType %v", tt.path, reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("get(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+	// whole document is a copy: changing it does not change config
+	doc, _ := fl.GetValueByPointer("")
+	doc.(map[string]interface{})["section1"].(map[string]interface{})["subsection2"] = "changed"
+	if v, err := fl.GetValue("section1", "subsection2", "value"); err != nil || 5.0 != v {
+		t.Errorf("GetValue() after changing whole document = %v, %v, want 5", v, err)
+	}
+	if _, err := (&HJSONConfig{}).GetValueByPointer(""); nil == err {
+		t.Errorf("GetValueByPointer(\"\") of not initialized config error = nil")
+	}
+}
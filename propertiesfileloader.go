@@ -0,0 +1,177 @@
+package configuration
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PropertiesConfig is configuration loader for Java-style .properties files
+// dotted keys like db.pool.size are exploded into nested maps,
+// so value is got by GetValue("db", "pool", "size")
+type PropertiesConfig struct {
+	HJSONConfig
+}
+
+// propertiesParser parses .properties contents for HJSONConfig internals
+type propertiesParser struct{}
+
+// ParseStringContents parses .properties file contents
+func (p propertiesParser) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	m = map[string]interface{}{}
+	scanner := bufio.NewScanner(bytes.NewReader(cnt))
+	lineNo := 0
+	logical := ""
+	startNo := 0
+	continued := false
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if !continued {
+			if "" == line || line[0] == '#' || line[0] == '!' {
+				continue
+			}
+			logical = ""
+			startNo = lineNo
+		}
+		// odd count of trailing backslashes means line continues
+		slashes := len(line) - len(strings.TrimRight(line, `\`))
+		continued = slashes%2 == 1
+		if continued {
+			line = line[:len(line)-1]
+		}
+		logical += line
+		if continued {
+			continue
+		}
+		if err = p.addProperty(m, logical, startNo); err != nil {
+			return nil, err
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if continued {
+		if err = p.addProperty(m, logical, startNo); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// addProperty splits logical line to key and value and puts value to nested map by dotted key
+func (p propertiesParser) addProperty(m map[string]interface{}, line string, lineNo int) error {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			keyEnd = i
+			break
+		}
+	}
+	rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	key, err := unescapeProperty(line[:keyEnd])
+	if err != nil {
+		return NewConfigParseError(fmt.Sprintf("properties line %d: %v", lineNo, err))
+	}
+	val, err := unescapeProperty(rest)
+	if err != nil {
+		return NewConfigParseError(fmt.Sprintf("properties line %d: %v", lineNo, err))
+	}
+	path := strings.Split(key, ".")
+	current := m
+	for i, part := range path {
+		if "" == part {
+			return NewConfigParseError(fmt.Sprintf("properties line %d: empty path element in key %q", lineNo, key))
+		}
+		if i == len(path)-1 {
+			if _, ok := current[part].(map[string]interface{}); ok {
+				return NewConfigParseError(fmt.Sprintf("properties line %d: key %q conflicts with nested keys", lineNo, key))
+			}
+			current[part] = inferScalar(val)
+			break
+		}
+		switch v := current[part].(type) {
+		case nil:
+			next := map[string]interface{}{}
+			current[part] = next
+			current = next
+		case map[string]interface{}:
+			current = v
+		default:
+			return NewConfigParseError(fmt.Sprintf("properties line %d: key %q conflicts with value of %q", lineNo, key, strings.Join(path[:i+1], ".")))
+		}
+	}
+	return nil
+}
+
+// unescapeProperty processes .properties escape sequences
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\uxxxx escape")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\uxxxx escape")
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// ParseStringContents parses .properties - separated to method cause I want test that
+func (fl *PropertiesConfig) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	return propertiesParser{}.ParseStringContents(cnt)
+}
+
+// SetDefaultLoadSetting sets default config file for loader
+// arguments are the same as HJSONConfig.SetDefaultLoadSetting has
+func (fl *PropertiesConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	fl.setParser(propertiesParser{})
+	return fl.HJSONConfig.SetDefaultLoadSetting(sl...)
+}
+
+// NewPropertiesConfig creates new object or gives error
+// all arguments are same as HJSONConfig.SetDefaultLoadSetting
+func NewPropertiesConfig(sl ...interface{}) (fl *PropertiesConfig, err error) {
+	fl = &PropertiesConfig{}
+	err = fl.SetDefaultLoadSetting(sl...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
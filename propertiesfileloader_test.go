@@ -0,0 +1,111 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropertiesConfig_ParseStringContents(t *testing.T) {
+	type args struct {
+		cnt []byte
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantM       map[string]interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name:        "value conflicts with nested keys",
+			args:        args{cnt: []byte("db=1\ndb.pool=2\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name:        "nested keys conflict with value",
+			args:        args{cnt: []byte("db.pool=2\ndb=1\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name:        "empty path element",
+			args:        args{cnt: []byte("db..pool=2\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name:        "malformed unicode escape",
+			args:        args{cnt: []byte("a=\\u12\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigParseError",
+		},
+		{
+			name: "separators, comments, escapes and continuation",
+			args: args{cnt: []byte("# comment\n! comment\ndb.pool.size=5\ndb.pool.name : main\ndb.flag true\nkey\\ with\\=escapes = a\\tb\\u0041\nlong = one \\\n    two\nempty=\n")},
+			wantM: map[string]interface{}{
+				"db": map[string]interface{}{
+					"pool": map[string]interface{}{
+						"size": float64(5),
+						"name": "main",
+					},
+					"flag": true,
+				},
+				"key with=escapes": "a\tbA",
+				"long":             "one two",
+				"empty":            "",
+			},
+			wantErr:     false,
+			wantErrType: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &PropertiesConfig{}
+			gotM, err := fl.ParseStringContents(tt.args.cnt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PropertiesConfig.ParseStringContents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("PropertiesConfig.ParseStringContents() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if !reflect.DeepEqual(gotM, tt.wantM) {
+				t.Errorf("PropertiesConfig.ParseStringContents() = %v, want %v", gotM, tt.wantM)
+			}
+		})
+	}
+}
+
+func TestNewPropertiesConfig(t *testing.T) {
+	fl, err := NewPropertiesConfig("./test.properties")
+	if err != nil {
+		t.Errorf("NewPropertiesConfig() error = %v", err)
+		return
+	}
+	i, err := fl.GetIntValue("db", "pool", "size")
+	if err != nil || i != 5 {
+		t.Errorf("PropertiesConfig.GetIntValue() = %v, %v, want 5", i, err)
+	}
+	s, err := fl.GetStringValue("db", "name")
+	if err != nil || s != "long name" {
+		t.Errorf("PropertiesConfig.GetStringValue() = %q, %v, want \"long name\"", s, err)
+	}
+	_, err = fl.GetBooleanValue("db", "url")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+		t.Errorf("PropertiesConfig.GetBooleanValue() error = %v, want ConfigTypeMismatchError", err)
+	}
+	_, err = fl.GetValue("db", "nothing")
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigItemNotFound" {
+		t.Errorf("PropertiesConfig.GetValue() error = %v, want ConfigItemNotFound", err)
+	}
+	if err = fl.CheckExternalConfig(); err != nil {
+		t.Errorf("PropertiesConfig.CheckExternalConfig() error = %v", err)
+	}
+}
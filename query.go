@@ -0,0 +1,578 @@
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryResult is one value found by Query
+type QueryResult struct {
+	// Path is concrete path to value, it may be given to GetValue as is
+	Path  []string
+	Value interface{}
+}
+
+// Query finds all values matching JSONPath-like expression. Supported syntax:
+//
+//	$                 root
+//	.key ['key']      object key, ["key"] works too
+//	.* [*]            all object values or array items
+//	[2] [-1]          array item, negative index counts from the end
+//	..key ..* ..[2]   recursive descent: selector is applied to value and all values inside it
+//	[?(@.enabled==true)]  filter of object values or array items: ==, !=, <, <=, >, >= comparisons
+//	                  with numbers, 'strings', true, false, null; [?(@.key)] checks key exists.
+//	                  Conditions are combined by && and ||
+//
+// e.g. $.backends[?(@.enabled==true)].host gives hosts of all enabled backends.
+// Object keys are walked in sorted order so results are always in the same order
+func (fl *HJSONConfig) Query(expr string) (res []QueryResult, err error) {
+	segments, err := parseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	m := fl.getMap()
+	if nil == m {
+		return nil, NewConfigUsageError("No config was initialized yet")
+	}
+	nodes := []QueryResult{{Path: []string{}, Value: m}}
+	for _, s := range segments {
+		next := []QueryResult{}
+		for _, n := range nodes {
+			if !s.descendant {
+				next = s.selector.apply(n, next)
+				continue
+			}
+			for _, d := range queryDescendants(n, nil) {
+				next = s.selector.apply(d, next)
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// querySegment is one step of query
+type querySegment struct {
+	// descendant is true for recursive descent(..)
+	descendant bool
+	selector   querySelector
+}
+
+// querySelector selects values from query node and adds them to res
+type querySelector interface {
+	apply(node QueryResult, res []QueryResult) []QueryResult
+}
+
+// queryName selects object value by key
+type queryName string
+
+func (s queryName) apply(node QueryResult, res []QueryResult) []QueryResult {
+	if m, ok := node.Value.(map[string]interface{}); ok {
+		if v, ok := m[string(s)]; ok {
+			res = append(res, QueryResult{Path: appendPath(node.Path, string(s)), Value: v})
+		}
+	}
+	return res
+}
+
+// queryWildcard selects all object values or array items
+type queryWildcard struct{}
+
+func (s queryWildcard) apply(node QueryResult, res []QueryResult) []QueryResult {
+	return append(res, queryChildren(node)...)
+}
+
+// queryIndex selects array item. negative index counts from the end
+type queryIndex int
+
+func (s queryIndex) apply(node QueryResult, res []QueryResult) []QueryResult {
+	if l, ok := node.Value.([]interface{}); ok {
+		idx := int(s)
+		if idx < 0 {
+			idx += len(l)
+		}
+		if idx >= 0 && idx < len(l) {
+			res = append(res, QueryResult{Path: appendPath(node.Path, strconv.Itoa(idx)), Value: l[idx]})
+		}
+	}
+	return res
+}
+
+// queryFilter selects object values or array items matching condition
+type queryFilter struct {
+	cond queryCondition
+}
+
+func (s queryFilter) apply(node QueryResult, res []QueryResult) []QueryResult {
+	for _, c := range queryChildren(node) {
+		if s.cond.match(c.Value) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// queryChildren gives object values in sorted key order or array items
+func queryChildren(node QueryResult) []QueryResult {
+	res := []QueryResult{}
+	switch v := node.Value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			res = append(res, QueryResult{Path: appendPath(node.Path, k), Value: v[k]})
+		}
+	case []interface{}:
+		for i, item := range v {
+			res = append(res, QueryResult{Path: appendPath(node.Path, strconv.Itoa(i)), Value: item})
+		}
+	}
+	return res
+}
+
+// queryDescendants gives node and all values inside it, parents go before children
+func queryDescendants(node QueryResult, res []QueryResult) []QueryResult {
+	res = append(res, node)
+	for _, c := range queryChildren(node) {
+		res = queryDescendants(c, res)
+	}
+	return res
+}
+
+// queryCondition is filter condition
+type queryCondition interface {
+	match(v interface{}) bool
+}
+
+// queryOr is true if any of conditions is true
+type queryOr []queryCondition
+
+func (c queryOr) match(v interface{}) bool {
+	for _, cond := range c {
+		if cond.match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryAnd is true if all conditions are true
+type queryAnd []queryCondition
+
+func (c queryAnd) match(v interface{}) bool {
+	for _, cond := range c {
+		if !cond.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// queryExists is true if @ path exists
+type queryExists struct {
+	operand queryOperand
+}
+
+func (c queryExists) match(v interface{}) bool {
+	_, ok := c.operand.resolve(v)
+	return ok
+}
+
+// queryCompare compares two operands
+type queryCompare struct {
+	op          string
+	left, right queryOperand
+}
+
+func (c queryCompare) match(v interface{}) bool {
+	l, ok := c.left.resolve(v)
+	if !ok {
+		return false
+	}
+	r, ok := c.right.resolve(v)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "==":
+		return reflect.DeepEqual(queryNormalize(l), queryNormalize(r))
+	case "!=":
+		return !reflect.DeepEqual(queryNormalize(l), queryNormalize(r))
+	}
+	if lf, ok := numberValue(l); ok {
+		if rf, ok := numberValue(r); ok {
+			return queryOrder(c.op, lf < rf, lf == rf)
+		}
+		return false
+	}
+	ls, lok := l.(string)
+	rs, rok := r.(string)
+	if lok && rok {
+		return queryOrder(c.op, ls < rs, ls == rs)
+	}
+	return false
+}
+
+// queryNormalize makes numbers comparable
+func queryNormalize(v interface{}) interface{} {
+	if f, ok := numberValue(v); ok {
+		return f
+	}
+	return v
+}
+
+// queryOrder gives result of ordering operator
+func queryOrder(op string, less bool, equal bool) bool {
+	switch op {
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	default:
+		return !less
+	}
+}
+
+// queryOperand is @ path or literal value
+type queryOperand struct {
+	current bool
+	path    []string
+	value   interface{}
+}
+
+// resolve gives operand value for filtered value v
+func (o queryOperand) resolve(v interface{}) (interface{}, bool) {
+	if !o.current {
+		return o.value, true
+	}
+	for _, key := range o.path {
+		switch c := v.(type) {
+		case map[string]interface{}:
+			item, ok := c[key]
+			if !ok {
+				return nil, false
+			}
+			v = item
+		case []interface{}:
+			item, err := arrayItem(c, key)
+			if err != nil {
+				return nil, false
+			}
+			v = item
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// queryParser parses query expression
+type queryParser struct {
+	expr string
+	pos  int
+}
+
+// parseQuery parses query expression into segments
+func parseQuery(expr string) ([]querySegment, error) {
+	p := &queryParser{expr: strings.TrimSpace(expr)}
+	if !p.consume("$") {
+		return nil, p.fail("query must start with $")
+	}
+	segments := []querySegment{}
+	for !p.end() {
+		s := querySegment{}
+		var err error
+		switch {
+		case p.consume(".."):
+			s.descendant = true
+			if p.peek() == '[' {
+				s.selector, err = p.bracket()
+			} else {
+				s.selector, err = p.dotName()
+			}
+		case p.consume("."):
+			s.selector, err = p.dotName()
+		case p.peek() == '[':
+			s.selector, err = p.bracket()
+		default:
+			err = p.fail("expected . or [")
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+	return segments, nil
+}
+
+// fail makes parse error pointing to current position
+func (p *queryParser) fail(msg string) error {
+	return NewConfigUsageError(fmt.Sprintf("Wrong query %q at position %d: %s", p.expr, p.pos, msg))
+}
+
+func (p *queryParser) end() bool {
+	return p.pos >= len(p.expr)
+}
+
+// peek gives current char or 0 at the end
+func (p *queryParser) peek() byte {
+	if p.end() {
+		return 0
+	}
+	return p.expr[p.pos]
+}
+
+// consume skips s if expression continues with it
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.pos++
+	}
+}
+
+// name reads unquoted key
+func (p *queryParser) name() string {
+	start := p.pos
+	for !p.end() && !strings.ContainsRune(" \t.[]()=!<>&|", rune(p.peek())) {
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+// dotName reads key or * after dot
+func (p *queryParser) dotName() (querySelector, error) {
+	if p.consume("*") {
+		return queryWildcard{}, nil
+	}
+	name := p.name()
+	if "" == name {
+		return nil, p.fail("expected key")
+	}
+	return queryName(name), nil
+}
+
+// bracket reads [...] selector
+func (p *queryParser) bracket() (sel querySelector, err error) {
+	p.pos++
+	p.skipSpaces()
+	switch c := p.peek(); {
+	case '*' == c:
+		p.pos++
+		sel = queryWildcard{}
+	case '\'' == c || '"' == c:
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		sel = queryName(s)
+	case '?' == c:
+		p.pos++
+		if !p.consume("(") {
+			return nil, p.fail("expected ( after ?")
+		}
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.fail("expected )")
+		}
+		sel = queryFilter{cond: cond}
+	default:
+		idx, err := p.index()
+		if err != nil {
+			return nil, err
+		}
+		sel = queryIndex(idx)
+	}
+	p.skipSpaces()
+	if !p.consume("]") {
+		return nil, p.fail("expected ]")
+	}
+	return sel, nil
+}
+
+// index reads array index
+func (p *queryParser) index() (int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	idx, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.fail("expected array index, quoted key, * or filter")
+	}
+	return idx, nil
+}
+
+// quoted reads 'string' or "string". backslash escapes quote and backslash
+func (p *queryParser) quoted() (string, error) {
+	quote := p.peek()
+	p.pos++
+	var b strings.Builder
+	for !p.end() {
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case '\\' == c && !p.end():
+			b.WriteByte(p.peek())
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.fail("string is not closed")
+}
+
+// or reads conditions joined by ||
+func (p *queryParser) or() (queryCondition, error) {
+	res := queryOr{}
+	for {
+		cond, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, cond)
+		p.skipSpaces()
+		if !p.consume("||") {
+			break
+		}
+	}
+	if 1 == len(res) {
+		return res[0], nil
+	}
+	return res, nil
+}
+
+// and reads conditions joined by &&
+func (p *queryParser) and() (queryCondition, error) {
+	res := queryAnd{}
+	for {
+		cond, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, cond)
+		p.skipSpaces()
+		if !p.consume("&&") {
+			break
+		}
+	}
+	if 1 == len(res) {
+		return res[0], nil
+	}
+	return res, nil
+}
+
+// comparison reads comparison or @ path existence check
+func (p *queryParser) comparison() (queryCondition, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			return queryCompare{op: op, left: left, right: right}, nil
+		}
+	}
+	if !left.current {
+		return nil, p.fail("expected comparison operator")
+	}
+	return queryExists{operand: left}, nil
+}
+
+// operand reads @ path or literal
+func (p *queryParser) operand() (o queryOperand, err error) {
+	p.skipSpaces()
+	switch c := p.peek(); {
+	case '@' == c:
+		p.pos++
+		o.current = true
+		o.path = []string{}
+		for {
+			if p.consume(".") {
+				name := p.name()
+				if "" == name {
+					return o, p.fail("expected key")
+				}
+				o.path = append(o.path, name)
+				continue
+			}
+			if p.peek() != '[' {
+				return o, nil
+			}
+			p.pos++
+			if c := p.peek(); '\'' == c || '"' == c {
+				name, err := p.quoted()
+				if err != nil {
+					return o, err
+				}
+				o.path = append(o.path, name)
+			} else {
+				idx, err := p.index()
+				if err != nil {
+					return o, err
+				}
+				o.path = append(o.path, strconv.Itoa(idx))
+			}
+			if !p.consume("]") {
+				return o, p.fail("expected ]")
+			}
+		}
+	case '\'' == c || '"' == c:
+		o.value, err = p.quoted()
+		return o, err
+	}
+	if '-' == p.peek() || (p.peek() >= '0' && p.peek() <= '9') {
+		start := p.pos
+		for !p.end() && strings.ContainsRune("+-.eE0123456789", rune(p.peek())) {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return o, p.fail("wrong number")
+		}
+		o.value = f
+		return o, nil
+	}
+	word := p.name()
+	switch word {
+	case "true":
+		o.value = true
+	case "false":
+		o.value = false
+	case "null":
+		o.value = nil
+	default:
+		return o, p.fail("expected @, string, number, true, false or null")
+	}
+	return o, nil
+}
@@ -0,0 +1,200 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHJSONConfig_Query(t *testing.T) {
+	fl, err := NewHJSONConfig([]byte(`{
+  name: main
+  backends: [
+    {
+      host: a.example.com
+      enabled: true
+      weight: 10
+    }
+    {
+      host: b.example.com
+      enabled: false
+      weight: 5
+    }
+    {
+      host: c.example.com
+      enabled: true
+      weight: 1.5
+      tags: ["x.y", "canary"]
+    }
+  ]
+  cache: {
+    host: cache.local
+    "key.with.dots": 1
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type teststruct struct {
+		name        string
+		expr        string
+		want        []QueryResult
+		wantErr     bool
+		wantErrType string
+	}
+	host := func(i string, h string) QueryResult {
+		return QueryResult{Path: []string{"backends", i, "host"}, Value: h}
+	}
+	tests := []teststruct{
+		{
+			name: "root",
+			expr: "$.name",
+			want: []QueryResult{{Path: []string{"name"}, Value: "main"}},
+		},
+		{
+			name: "filter",
+			expr: "$.backends[?(@.enabled==true)].host",
+			want: []QueryResult{host("0", "a.example.com"), host("2", "c.example.com")},
+		},
+		{
+			name: "filter with spaces and number",
+			expr: "$.backends[?( @.weight >= 5 )].host",
+			want: []QueryResult{host("0", "a.example.com"), host("1", "b.example.com")},
+		},
+		{
+			name: "filter with fractional number",
+			expr: "$.backends[?(@.weight < 1.6)].host",
+			want: []QueryResult{host("2", "c.example.com")},
+		},
+		{
+			name: "filter with string and or",
+			expr: `$.backends[?(@.host=='b.example.com' || @.weight==1.5)].host`,
+			want: []QueryResult{host("1", "b.example.com"), host("2", "c.example.com")},
+		},
+		{
+			name: "filter with and",
+			expr: "$.backends[?(@.enabled==true && @.weight>2)].host",
+			want: []QueryResult{host("0", "a.example.com")},
+		},
+		{
+			name: "filter by existence",
+			expr: "$.backends[?(@.tags)].host",
+			want: []QueryResult{host("2", "c.example.com")},
+		},
+		{
+			name: "filter by array item",
+			expr: `$.backends[?(@.tags[-1]=="canary")].weight`,
+			want: []QueryResult{{Path: []string{"backends", "2", "weight"}, Value: 1.5}},
+		},
+		{
+			name: "wildcard",
+			expr: "$.backends[*].host",
+			want: []QueryResult{host("0", "a.example.com"), host("1", "b.example.com"), host("2", "c.example.com")},
+		},
+		{
+			name: "index",
+			expr: "$.backends[-1].tags[0]",
+			want: []QueryResult{{Path: []string{"backends", "2", "tags", "0"}, Value: "x.y"}},
+		},
+		{
+			name: "index out of range",
+			expr: "$.backends[3].host",
+			want: []QueryResult{},
+		},
+		{
+			name: "recursive descent",
+			expr: "$..host",
+			want: []QueryResult{
+				host("0", "a.example.com"), host("1", "b.example.com"), host("2", "c.example.com"),
+				{Path: []string{"cache", "host"}, Value: "cache.local"},
+			},
+		},
+		{
+			name: "recursive descent with filter",
+			expr: "$..[?(@ == 'canary')]",
+			want: []QueryResult{{Path: []string{"backends", "2", "tags", "1"}, Value: "canary"}},
+		},
+		{
+			name: "quoted key",
+			expr: `$.cache['key.with.dots']`,
+			want: []QueryResult{{Path: []string{"cache", "key.with.dots"}, Value: 1.0}},
+		},
+		{
+			name: "object wildcard in sorted order",
+			expr: `$["cache"].*`,
+			want: []QueryResult{
+				{Path: []string{"cache", "host"}, Value: "cache.local"},
+				{Path: []string{"cache", "key.with.dots"}, Value: 1.0},
+			},
+		},
+		{
+			name: "nothing found",
+			expr: "$.nothing..host",
+			want: []QueryResult{},
+		},
+		{
+			name:        "no root",
+			expr:        "backends",
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "not closed bracket",
+			expr:        "$.backends[0",
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "not closed filter",
+			expr:        "$.backends[?(@.enabled==true]",
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "literal without comparison",
+			expr:        "$.backends[?(true)]",
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "not closed string",
+			expr:        "$['cache]",
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "empty key",
+			expr:        "$.backends.",
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fl.Query(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HJSONConfig.Query() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("HJSONConfig.Query() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HJSONConfig.Query() = %v, want %v", got, tt.want)
+			}
+			// result paths go to GetValue as is
+			for _, r := range got {
+				v, err := fl.GetValue(r.Path...)
+				if err != nil || !reflect.DeepEqual(v, r.Value) {
+					t.Errorf("HJSONConfig.GetValue(%v) = %v, %v, want %v", r.Path, v, err, r.Value)
+				}
+			}
+		})
+	}
+}
+
+func TestHJSONConfig_Query_notInitialized(t *testing.T) {
+	_, err := (&HJSONConfig{}).Query("$.a")
+	if _, ok := err.(*ConfigUsageError); !ok {
+		t.Errorf("HJSONConfig.Query() error = %v, want ConfigUsageError", err)
+	}
+}
@@ -0,0 +1,132 @@
+package configuration
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// ReloadResult is ReloadAll result for one tagged config
+type ReloadResult struct {
+	Tag string
+	// Skipped is true for configs which are not file backed
+	Skipped bool
+	// Reloaded is true if config internal map was reloaded
+	Reloaded bool
+	// Err is CheckExternalConfig or ReloadInternalMap error
+	Err error
+}
+
+// ReloadAll reloads all configs tagged by GetConfigInstance.
+// It's all-or-nothing for file configs of built-in formats(HJSON, YAML, TOML, INI, properties):
+// their files are loaded and parsed first and internal maps are swapped only if every file was parsed
+// and every other config was reloaded. Other configs(own formats, EnvConfig, LayeredConfig and so on)
+// are checked by CheckExternalConfig with the rest and reloaded by ReloadInternalMap after that,
+// so if their files change between check and reload they still may fail alone.
+// Configs which are not file backed(ConfigUsageError on check) are skipped.
+// Report is sorted by tag. err is ConfigReloadError if something was not reloaded
+func ReloadAll() (report []ReloadResult, err error) {
+	intConfigMutex.Lock()
+	tags := make([]string, 0, len(intConfigHash))
+	configs := make(map[string]IConfig, len(intConfigHash))
+	for tag, c := range intConfigHash {
+		tags = append(tags, tag)
+		configs[tag] = c
+	}
+	intConfigMutex.Unlock()
+	sort.Strings(tags)
+	report = make([]ReloadResult, len(tags))
+	// staged are parsed maps of file configs swapped at the end
+	staged := make([]map[string]interface{}, len(tags))
+	failed := []string{}
+	for i, tag := range tags {
+		report[i].Tag = tag
+		var e error
+		if base := fileConfigBase(configs[tag]); nil != base {
+			staged[i], e = base.loadExternalMap()
+		} else {
+			e = configs[tag].CheckExternalConfig()
+		}
+		switch {
+		case isUsageError(e):
+			report[i].Skipped = true
+		case nil != e:
+			report[i].Err = e
+			failed = append(failed, tag+": "+e.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return report, NewConfigReloadError("Configs were not reloaded cause check failed: " + strings.Join(failed, "; "))
+	}
+	for i, tag := range tags {
+		if report[i].Skipped || nil != staged[i] {
+			continue
+		}
+		// file may be changed again after check so it still may fail there
+		if e := configs[tag].ReloadInternalMap(); nil != e {
+			report[i].Err = e
+			failed = append(failed, tag+": "+e.Error())
+			continue
+		}
+		report[i].Reloaded = true
+	}
+	if len(failed) > 0 {
+		return report, NewConfigReloadError("Configs reload failed: " + strings.Join(failed, "; "))
+	}
+	for i, tag := range tags {
+		if nil != staged[i] {
+			fileConfigBase(configs[tag]).setMap(staged[i])
+			report[i].Reloaded = true
+		}
+	}
+	return report, nil
+}
+
+// fileConfigBase gives HJSONConfig of built-in file config. Their reload is file parse only,
+// so ReloadAll may parse file first and swap map later. nil for other configs
+func fileConfigBase(c IConfig) *HJSONConfig {
+	switch v := c.(type) {
+	case *HJSONConfig:
+		return v
+	case *YAMLConfig:
+		return &v.HJSONConfig
+	case *TOMLConfig:
+		return &v.HJSONConfig
+	case *INIConfig:
+		return &v.HJSONConfig
+	case *PropertiesConfig:
+		return &v.HJSONConfig
+	default:
+		return nil
+	}
+}
+
+// HandleSIGHUP calls ReloadAll every time process gets SIGHUP until ctx is done.
+// callback(may be nil) gets ReloadAll results.
+// Handler is opt-in: nothing listens SIGHUP until this function is called
+func HandleSIGHUP(ctx context.Context, callback func(report []ReloadResult, err error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(signals)
+		handleReloadSignals(ctx, signals, callback)
+	}()
+}
+
+// handleReloadSignals runs ReloadAll for every signal got from channel
+func handleReloadSignals(ctx context.Context, signals <-chan os.Signal, callback func(report []ReloadResult, err error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			report, err := ReloadAll()
+			if nil != callback {
+				callback(report, err)
+			}
+		}
+	}
+}
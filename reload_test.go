@@ -0,0 +1,178 @@
+package configuration
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// reloadTestSetup makes temp dir and fresh config registry restored after test
+func reloadTestSetup(t *testing.T) (dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "configuration-reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := intConfigHash
+	intConfigHash = map[string]IConfig{}
+	return dir, func() {
+		intConfigHash = saved
+		os.RemoveAll(dir)
+	}
+}
+
+// reloadTestFile writes config file with value given
+func reloadTestFile(t *testing.T, filename string, cnt string) {
+	if err := ioutil.WriteFile(filename, []byte(cnt), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadAll(t *testing.T) {
+	dir, cleanup := reloadTestSetup(t)
+	defer cleanup()
+	first := filepath.Join(dir, "first.hjson")
+	second := filepath.Join(dir, "second.yaml")
+	reloadTestFile(t, first, "{\n  value: 1\n}\n")
+	reloadTestFile(t, second, "value: 1\n")
+	if _, err := GetConfigInstance("first", "hjson", first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetConfigInstance("second", "yaml", second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetConfigInstance("inmemory", "hjson", map[string]interface{}{"value": 1.0}); err != nil {
+		t.Fatal(err)
+	}
+	// broken file: nothing is reloaded
+	reloadTestFile(t, first, "{\n  value: 2\n}\n")
+	reloadTestFile(t, second, "value: [2\n")
+	report, err := ReloadAll()
+	if _, ok := err.(*ConfigReloadError); !ok {
+		t.Fatalf("ReloadAll() error = %v, want ConfigReloadError", err)
+	}
+	if len(report) != 3 || report[2].Tag != "second" || nil == report[2].Err {
+		t.Errorf("ReloadAll() report = %+v, want second failed", report)
+	}
+	for _, r := range report {
+		if r.Reloaded {
+			t.Errorf("ReloadAll() reloaded %s while other check failed", r.Tag)
+		}
+	}
+	for _, tag := range []string{"first", "second"} {
+		c, _ := GetConfigInstance(tag)
+		if i, _ := c.GetIntValue("value"); i != 1 {
+			t.Errorf("%s value after failed ReloadAll() = %v, want 1", tag, i)
+		}
+	}
+	// everything is fine
+	reloadTestFile(t, second, "value: 2\n")
+	report, err = ReloadAll()
+	if err != nil {
+		t.Fatalf("ReloadAll() error = %v", err)
+	}
+	want := []ReloadResult{
+		{Tag: "first", Reloaded: true},
+		{Tag: "inmemory", Skipped: true},
+		{Tag: "second", Reloaded: true},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("ReloadAll() report = %+v, want %+v", report, want)
+	}
+	for _, tag := range []string{"first", "second"} {
+		c, _ := GetConfigInstance(tag)
+		if i, _ := c.GetIntValue("value"); i != 2 {
+			t.Errorf("%s value after ReloadAll() = %v, want 2", tag, i)
+		}
+	}
+}
+
+// reloadTestFailing is config which passes check but fails reload like file changed in between
+type reloadTestFailing struct {
+	HJSONConfig
+}
+
+// CheckExternalConfig is IConfig method
+func (fl *reloadTestFailing) CheckExternalConfig() error {
+	return nil
+}
+
+// ReloadInternalMap is IConfig method
+func (fl *reloadTestFailing) ReloadInternalMap() error {
+	return NewConfigParseError("changed after check")
+}
+
+func TestReloadAll_failedAfterCheck(t *testing.T) {
+	dir, cleanup := reloadTestSetup(t)
+	defer cleanup()
+	first := filepath.Join(dir, "first.yaml")
+	reloadTestFile(t, first, "value: 1\n")
+	c, err := GetConfigInstance("first", "yaml", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intConfigHash["other"] = &reloadTestFailing{}
+	reloadTestFile(t, first, "value: 2\n")
+	report, err := ReloadAll()
+	if _, ok := err.(*ConfigReloadError); !ok {
+		t.Fatalf("ReloadAll() error = %v, want ConfigReloadError", err)
+	}
+	if len(report) != 2 || report[0].Reloaded || nil == report[1].Err {
+		t.Errorf("ReloadAll() report = %+v, want first not reloaded and other failed", report)
+	}
+	if i, _ := c.GetIntValue("value"); i != 1 {
+		t.Errorf("first value after failed ReloadAll() = %v, want 1", i)
+	}
+}
+
+func TestReloadAll_empty(t *testing.T) {
+	_, cleanup := reloadTestSetup(t)
+	defer cleanup()
+	report, err := ReloadAll()
+	if err != nil || len(report) != 0 {
+		t.Errorf("ReloadAll() = %v, %v, want empty report", report, err)
+	}
+}
+
+func TestHandleReloadSignals(t *testing.T) {
+	dir, cleanup := reloadTestSetup(t)
+	defer cleanup()
+	filename := filepath.Join(dir, "app.hjson")
+	reloadTestFile(t, filename, "{\n  value: 1\n}\n")
+	c, err := GetConfigInstance("app", "hjson", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloadTestFile(t, filename, "{\n  value: 2\n}\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal)
+	results := make(chan []ReloadResult, 1)
+	done := make(chan struct{})
+	go func() {
+		handleReloadSignals(ctx, signals, func(report []ReloadResult, err error) {
+			if err != nil {
+				t.Errorf("ReloadAll() error = %v", err)
+			}
+			results <- report
+		})
+		close(done)
+	}()
+	signals <- syscall.SIGHUP
+	select {
+	case report := <-results:
+		if len(report) != 1 || !report[0].Reloaded {
+			t.Errorf("ReloadAll() report = %+v, want app reloaded", report)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal was not handled")
+	}
+	if i, _ := c.GetIntValue("value"); i != 2 {
+		t.Errorf("value after signal = %v, want 2", i)
+	}
+	cancel()
+	<-done
+}
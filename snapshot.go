@@ -0,0 +1,58 @@
+package configuration
+
+// snapshotConfig is read-only config made by Snapshot. It's internal map is deep copy
+// nobody else has, so values never change. All mutating methods give ConfigUsageError
+type snapshotConfig struct {
+	HJSONConfig
+}
+
+// Snapshot returns read-only deep copy of current configuration.
+// Use it to read several values consistently while config may be reloaded:
+// snapshot never changes, reloads affect config it was made from only
+func (fl *HJSONConfig) Snapshot() IConfig {
+	m := fl.getMap()
+	if nil != m {
+		m = deepCopyValue(m).(map[string]interface{})
+	}
+	return fl.snapshotOf(m)
+}
+
+// snapshotOf makes read-only config of map with getter options of fl.
+// m is not copied, so it must be map nobody changes: deep copy or map swapped out on reload
+func (fl *HJSONConfig) snapshotOf(m map[string]interface{}) IConfig {
+	return &snapshotConfig{HJSONConfig: HJSONConfig{hjsonMap: m, options: fl.getOptions()}}
+}
+
+// Snapshot returns snapshot itself cause it never changes
+func (fl *snapshotConfig) Snapshot() IConfig {
+	return fl
+}
+
+// SetDefaultLoadSetting is not allowed for snapshot
+func (fl *snapshotConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	return NewConfigUsageError("Config snapshot is read-only")
+}
+
+// CheckExternalConfig is not allowed for snapshot
+func (fl *snapshotConfig) CheckExternalConfig() (err error) {
+	return NewConfigUsageError("Config snapshot is not file backed")
+}
+
+// ReloadInternalMap is not allowed for snapshot
+func (fl *snapshotConfig) ReloadInternalMap() (err error) {
+	return NewConfigUsageError("Config snapshot is read-only")
+}
+
+// GetSubconfig returns read-only part of snapshot
+func (fl *snapshotConfig) GetSubconfig(path ...string) (c IConfig, err error) {
+	i1, err1 := fl.GetValue(path...)
+	if nil != err1 {
+		return nil, err1
+	}
+	switch v := i1.(type) {
+	case map[string]interface{}:
+		return &snapshotConfig{HJSONConfig: HJSONConfig{hjsonMap: v, options: fl.options}}, nil
+	default:
+		return nil, NewConfigTypeMismatchError("Wrong value type detected")
+	}
+}
@@ -0,0 +1,128 @@
+package configuration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHJSONConfig_Snapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configuration-snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "app.hjson")
+	if err = ioutil.WriteFile(filename, []byte("{\n  host: old\n  port: 1\n  list: [1, 2]\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fl, err := NewHJSONConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := fl.Snapshot()
+	if err = ioutil.WriteFile(filename, []byte("{\n  host: new\n  port: 2\n  list: [3]\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = fl.ReloadInternalMap(); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := snap.GetStringValue("host"); s != "old" {
+		t.Errorf("snapshot host = %v, want old", s)
+	}
+	if i, _ := snap.GetIntValue("port"); i != 1 {
+		t.Errorf("snapshot port = %v, want 1", i)
+	}
+	if s, _ := fl.GetStringValue("host"); s != "new" {
+		t.Errorf("config host = %v, want new", s)
+	}
+	// snapshot does not share memory with config
+	l, _ := snap.GetValue("list")
+	l.([]interface{})[0] = 100.0
+	if v, _ := fl.GetValue("list"); fmt.Sprint(v) != "[3]" {
+		t.Errorf("config list = %v, want [3]", v)
+	}
+	if snap.Snapshot() != snap {
+		t.Errorf("snapshot of snapshot must be the same object")
+	}
+}
+
+func TestHJSONConfig_Snapshot_readOnly(t *testing.T) {
+	fl, err := NewHJSONConfig(map[string]interface{}{"section": map[string]interface{}{"value": 1.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := fl.Snapshot()
+	sub, err := snap.GetSubconfig("section")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type teststruct struct {
+		name        string
+		call        func() error
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name:        "SetDefaultLoadSetting",
+			call:        func() error { return snap.SetDefaultLoadSetting(map[string]interface{}{}) },
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "CheckExternalConfig",
+			call:        snap.CheckExternalConfig,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "ReloadInternalMap",
+			call:        snap.ReloadInternalMap,
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name:        "subconfig SetDefaultLoadSetting",
+			call:        func() error { return sub.SetDefaultLoadSetting(map[string]interface{}{}) },
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+		{
+			name: "uninitialized config snapshot",
+			call: func() error {
+				_, err := (&HJSONConfig{}).Snapshot().GetValue("value")
+				return err
+			},
+			wantErrType: "*configuration.ConfigUsageError",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if nil == err {
+				t.Fatalf("%s error = nil, want %s", tt.name, tt.wantErrType)
+			}
+			if tt.wantErrType != reflect.TypeOf(err).String() {
+				t.Errorf("%s error type = %v, wantErrType %v", tt.name, reflect.TypeOf(err), tt.wantErrType)
+			}
+		})
+	}
+	if i, _ := sub.GetIntValue("value"); i != 1 {
+		t.Errorf("snapshot subconfig value = %v, want 1", i)
+	}
+}
+
+func TestLayeredConfig_Snapshot(t *testing.T) {
+	defaults, _ := NewHJSONConfig(map[string]interface{}{"port": 1.0, "host": "localhost"})
+	local, _ := NewHJSONConfig(map[string]interface{}{"port": 2.0})
+	lc, err := NewLayeredConfig(ConfigLayer{Name: "defaults", Config: defaults}, ConfigLayer{Name: "local", Config: local})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := lc.Snapshot()
+	if i, _ := snap.GetIntValue("port"); i != 2 {
+		t.Errorf("snapshot port = %v, want 2", i)
+	}
+	if s, _ := snap.GetStringValue("host"); s != "localhost" {
+		t.Errorf("snapshot host = %v, want localhost", s)
+	}
+}
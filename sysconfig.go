@@ -1,5 +1,10 @@
 package configuration
 
+import (
+	"sync"
+	"time"
+)
+
 // IConfig is basic interface for all optional configuration structures
 type IConfig interface {
 	// Set default config load settings. In case of file loader - filename
@@ -14,29 +19,59 @@ type IConfig interface {
 	// functions below will try make from this variant value typed value
 	// returns integer value by path
 	GetIntValue(path ...string) (i int, err error)
+	// returns int64 value by path. ConfigTypeMismatchError if it does not fit
+	GetInt64Value(path ...string) (i int64, err error)
+	// returns uint64 value by path. ConfigTypeMismatchError if it does not fit
+	GetUint64Value(path ...string) (i uint64, err error)
+	// returns float64 value by path
+	GetFloatValue(path ...string) (f float64, err error)
+	// returns duration by path: "30s" string or number of seconds
+	GetDurationValue(path ...string) (d time.Duration, err error)
+	// returns size in bytes by path: "10MiB", "1.5GB" string or number of bytes
+	GetByteSizeValue(path ...string) (size uint64, err error)
+	// returns time by path: RFC3339 string or unix time number
+	GetTimeValue(path ...string) (tm time.Time, err error)
+	// typed arrays by path. string "a,b,c" is array too. errors tell index of wrong item
+	GetStringSlice(path ...string) (res []string, err error)
+	GetIntSlice(path ...string) (res []int, err error)
+	GetFloatSlice(path ...string) (res []float64, err error)
+	GetBoolSlice(path ...string) (res []bool, err error)
+	// returns object by path as map
+	GetStringMap(path ...string) (m map[string]interface{}, err error)
+	// returns object with string values by path as map
+	GetStringMapString(path ...string) (m map[string]string, err error)
 	// returns string value or error by path
 	GetStringValue(path ...string) (s string, err error)
 	// returns boolean value
 	GetBooleanValue(path ...string) (b bool, err error)
 	// returns config interface or nil + error
 	GetSubconfig(path ...string) (c IConfig, err error)
+	// decodes value by path(whole config if path is empty) into struct, map, slice or other go value
+	// target must be pointer. struct fields are bound by `config:"name"` tags
+	Unmarshal(target interface{}, path ...string) (err error)
+	// returns read-only deep copy of configuration which never changes, e.g. for consistent reads while reload
+	Snapshot() IConfig
 }
 
 // this map is intended for GetConfigInstance
 var intConfigHash map[string]IConfig
 
+// intConfigMutex guards intConfigHash
+var intConfigMutex sync.Mutex
+
 func init() {
 	intConfigHash = map[string]IConfig{}
 }
 
 // GetConfigInstance get instans of config depending of wanted config format
-// for now  supported only HJSON format
+// supported formats are all ones registered with RegisterFormat:
+// HJSON, YAML, TOML, INI and Java .properties are registered by this package
 // tag is intended not to load configuration files twice and store object hash map inside module
 // thats done specially not to create config instance twice + get already created instance
 // How to call: GetConfigInstance(tag, format, list_of_settings...)
 // tag used not to load config twice. If you need reload them just use CheckExternalConfig() and ReloadInternalMap()
-// format: for now HJSON|hjson|JSON|json
-// this parameter added for future features(may be we would add other configuration formats there)
+// format: case insensitive format name or alias, e.g. HJSON|JSON|YAML|YML|TOML|INI|PROPERTIES
+// or name of format registered by RegisterFormat. See ListFormats()
 // if it was loaded from file.
 // settings must contain config type as first argument and other parameters SetDefaultLoadSetting need
 // For example.
@@ -69,7 +104,9 @@ func GetConfigInstance(settings ...interface{}) (config IConfig, err error) {
 		}
 	}
 	if hasTag {
+		intConfigMutex.Lock()
 		ic, ok := intConfigHash[tag]
+		intConfigMutex.Unlock()
 		if ok {
 			return ic, nil
 		}
@@ -85,24 +122,24 @@ func GetConfigInstance(settings ...interface{}) (config IConfig, err error) {
 	default:
 		return nil, NewConfigUsageError("Wrong format of type of config")
 	}
-	switch cType {
-	case "HJSON":
-		fallthrough
-	case "hjson":
-		fallthrough
-	case "JSON":
-		fallthrough
-	case "json":
-		// all other arguments to constructor
-		config, err = NewHJSONConfig(settings[2:]...)
-		if err != nil {
-			return nil, err
-		}
-		if hasTag {
-			intConfigHash[tag] = config
-		}
-		return config, nil
-	default:
+	factory, ok := getFormatFactory(cType)
+	if !ok {
 		return nil, NewConfigUsageError("Unknown configuration format:" + cType)
 	}
+	// all other arguments to constructor
+	config, err = factory(settings[2:]...)
+	if err != nil {
+		return nil, err
+	}
+	if hasTag {
+		// config is created without lock so factory may use GetConfigInstance too.
+		// If the same tag was created meanwhile first created instance wins
+		intConfigMutex.Lock()
+		defer intConfigMutex.Unlock()
+		if ic, ok := intConfigHash[tag]; ok {
+			return ic, nil
+		}
+		intConfigHash[tag] = config
+	}
+	return config, nil
 }
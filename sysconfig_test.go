@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -60,6 +61,62 @@ func TestGetConfigInstance(t *testing.T) {
 			wantErrType: "",
 			checker:     nil,
 		},
+		{
+			name: "nil first, YAML, correct second arg - yaml bytes. must get correct YAMLConfig",
+			args: args{
+				settings: []interface{}{nil, "yaml", []byte("test: test\n")},
+			},
+			wantConfig: &YAMLConfig{
+				HJSONConfig: HJSONConfig{filename: "", hjsonMap: map[string]interface{}{"test": "test"}, parser: yamlParser{}},
+			},
+			wantErr:     false,
+			wantErrType: "",
+			checker:     nil,
+		},
+		{
+			name: "nil first, TOML, correct second arg - toml bytes. must get correct TOMLConfig",
+			args: args{
+				settings: []interface{}{nil, "TOML", []byte("test = \"test\"\n")},
+			},
+			wantConfig: &TOMLConfig{
+				HJSONConfig: HJSONConfig{filename: "", hjsonMap: map[string]interface{}{"test": "test"}, parser: tomlParser{}},
+			},
+			wantErr:     false,
+			wantErrType: "",
+			checker:     nil,
+		},
+		{
+			name: "nil first, INI, correct second arg - ini bytes. must get correct INIConfig",
+			args: args{
+				settings: []interface{}{nil, "ini", []byte("[s]\ntest = test\n")},
+			},
+			wantConfig: &INIConfig{
+				HJSONConfig: HJSONConfig{
+					filename: "",
+					hjsonMap: map[string]interface{}{"s": map[string]interface{}{"test": "test"}},
+					parser:   iniParser{},
+				},
+			},
+			wantErr:     false,
+			wantErrType: "",
+			checker:     nil,
+		},
+		{
+			name: "nil first, PROPERTIES, correct second arg - properties bytes. must get correct PropertiesConfig",
+			args: args{
+				settings: []interface{}{nil, "properties", []byte("s.test=test\n")},
+			},
+			wantConfig: &PropertiesConfig{
+				HJSONConfig: HJSONConfig{
+					filename: "",
+					hjsonMap: map[string]interface{}{"s": map[string]interface{}{"test": "test"}},
+					parser:   propertiesParser{},
+				},
+			},
+			wantErr:     false,
+			wantErrType: "",
+			checker:     nil,
+		},
 		{
 			name: "check how tags do their work",
 			args: args{
@@ -113,3 +170,33 @@ func TestGetConfigInstance(t *testing.T) {
 		})
 	}
 }
+
+// run with -race to see it is really safe
+func TestGetConfigInstance_concurrent(t *testing.T) {
+	saved := intConfigHash
+	intConfigHash = map[string]IConfig{}
+	defer func() { intConfigHash = saved }()
+	configs := make([]IConfig, 20)
+	var wg sync.WaitGroup
+	for i := range configs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c, err := GetConfigInstance("concurrent", "hjson", map[string]interface{}{"value": float64(i)})
+			if err != nil {
+				t.Errorf("GetConfigInstance() error = %v", err)
+				return
+			}
+			configs[i] = c
+			if _, err = ReloadAll(); err != nil {
+				t.Errorf("ReloadAll() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i, c := range configs {
+		if c != configs[0] {
+			t.Errorf("GetConfigInstance() call %d gave other instance for the same tag", i)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package configuration
+
+import (
+	toml "github.com/BurntSushi/toml"
+)
+
+// TOMLConfig is configuration loader TOML interface
+// tables are normalized to map[string]interface{} so they are available as subconfigs,
+// numbers become float64(except big integers, see normalizeInt) and datetime values stay time.Time
+type TOMLConfig struct {
+	HJSONConfig
+}
+
+// tomlParser parses TOML contents for HJSONConfig internals
+type tomlParser struct{}
+
+// ParseStringContents parses TOML and normalizes result
+func (p tomlParser) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	raw := map[string]interface{}{}
+	_, err = toml.Decode(string(cnt), &raw)
+	if nil != err {
+		return nil, err
+	}
+	return normalizeValue(raw).(map[string]interface{}), nil
+}
+
+// ParseStringContents parses TOML - separated to method cause I want test that
+func (fl *TOMLConfig) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	return tomlParser{}.ParseStringContents(cnt)
+}
+
+// SetDefaultLoadSetting sets default config file for loader
+// arguments are the same as HJSONConfig.SetDefaultLoadSetting has
+func (fl *TOMLConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	fl.setParser(tomlParser{})
+	if len(sl) > 0 {
+		if v, ok := sl[0].(map[string]interface{}); ok {
+			return fl.HJSONConfig.SetDefaultLoadSetting(normalizeValue(v))
+		}
+	}
+	return fl.HJSONConfig.SetDefaultLoadSetting(sl...)
+}
+
+// NewTOMLConfig creates new object or gives error
+// all arguments are same as HJSONConfig.SetDefaultLoadSetting
+func NewTOMLConfig(sl ...interface{}) (fl *TOMLConfig, err error) {
+	fl = &TOMLConfig{}
+	err = fl.SetDefaultLoadSetting(sl...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
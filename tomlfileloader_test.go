@@ -0,0 +1,159 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTOMLConfig_ParseStringContents(t *testing.T) {
+	type args struct {
+		cnt []byte
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantM       map[string]interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name:        "Wrong broken toml",
+			args:        args{cnt: []byte("a = [b")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "",
+		},
+		{
+			name: "tables, arrays of tables and numbers are normalized",
+			args: args{cnt: []byte("a = 1\n[b]\nc = 1.5\nd = [1, 2]\n[[e]]\nf = \"x\"\n")},
+			wantM: map[string]interface{}{
+				"a": float64(1),
+				"b": map[string]interface{}{
+					"c": float64(1.5),
+					"d": []interface{}{float64(1), float64(2)},
+				},
+				"e": []interface{}{
+					map[string]interface{}{"f": "x"},
+				},
+			},
+			wantErr:     false,
+			wantErrType: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &TOMLConfig{}
+			gotM, err := fl.ParseStringContents(tt.args.cnt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TOMLConfig.ParseStringContents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("TOMLConfig.ParseStringContents() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if !reflect.DeepEqual(gotM, tt.wantM) {
+				t.Errorf("TOMLConfig.ParseStringContents() = %v, want %v", gotM, tt.wantM)
+			}
+		})
+	}
+}
+
+func TestNewTOMLConfig(t *testing.T) {
+	type args struct {
+		sl []interface{}
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantErr     bool
+		wantErrType string
+		checker     func(fl *TOMLConfig) bool
+	}
+	tests := []teststruct{
+		{
+			name:        "no arguments",
+			args:        args{sl: []interface{}{}},
+			wantErr:     true,
+			wantErrType: "*configuration.HJSONConfigError",
+		},
+		{
+			name:        "not existing file",
+			args:        args{sl: []interface{}{"/toml_not_existing_awful_filename"}},
+			wantErr:     true,
+			wantErrType: "*configuration.HJSONConfigError",
+		},
+		{
+			name:    "load from file",
+			args:    args{sl: []interface{}{"./test.toml"}},
+			wantErr: false,
+			checker: func(fl *TOMLConfig) bool {
+				c, err := fl.GetSubconfig("server")
+				if err != nil {
+					t.Errorf("TOMLConfig.GetSubconfig() error = %v", err)
+					return false
+				}
+				i, err := c.GetIntValue("port")
+				if err != nil || i != 8080 {
+					t.Errorf("subconfig GetIntValue() = %v, %v, want 8080", i, err)
+					return false
+				}
+				v, err := fl.GetValue("released")
+				want := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+				if tm, ok := v.(time.Time); err != nil || !ok || !tm.Equal(want) {
+					t.Errorf("TOMLConfig.GetValue() = %v, %v, want %v", v, err, want)
+					return false
+				}
+				v, err = fl.GetValue("backends")
+				if l, ok := v.([]interface{}); err != nil || !ok || len(l) != 2 {
+					t.Errorf("TOMLConfig.GetValue() = %v, %v, want array of 2 tables", v, err)
+					return false
+				}
+				if err = fl.CheckExternalConfig(); err != nil {
+					t.Errorf("TOMLConfig.CheckExternalConfig() error = %v", err)
+					return false
+				}
+				if err = fl.ReloadInternalMap(); err != nil {
+					t.Errorf("TOMLConfig.ReloadInternalMap() error = %v", err)
+					return false
+				}
+				return true
+			},
+		},
+		{
+			name:    "load from bytes",
+			args:    args{sl: []interface{}{[]byte("[a]\nb = true\n")}},
+			wantErr: false,
+			checker: func(fl *TOMLConfig) bool {
+				b, err := fl.GetBooleanValue("a", "b")
+				if err != nil || !b {
+					t.Errorf("TOMLConfig.GetBooleanValue() = %v, %v, want true", b, err)
+					return false
+				}
+				if err = fl.ReloadInternalMap(); err == nil {
+					t.Errorf("TOMLConfig.ReloadInternalMap() must fail on config not loaded from file")
+					return false
+				}
+				return true
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFl, err := NewTOMLConfig(tt.args.sl...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTOMLConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("NewTOMLConfig() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if nil != tt.checker {
+				tt.checker(gotFl)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package configuration
+
+import (
+	"context"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	// WatchDebounce is time Watch waits after last file change before reload
+	// so rapid writes give one reload
+	WatchDebounce = 100 * time.Millisecond
+	// WatchPollInterval is file check interval of polling watcher used if native one is not available
+	WatchPollInterval = time.Second
+)
+
+// fileWatcher gives names of changed files in watched directory
+type fileWatcher interface {
+	Events() <-chan string
+	Close() error
+}
+
+// ChangeCallback is called after config reload by Watch
+// old and new are read-only snapshots with values before and after reload,
+// they read values with the same getter options(SetStrictNumbers, SetTimeLayouts) watched config has
+type ChangeCallback func(old, new IConfig)
+
+// watchState keeps Watch callbacks. It's pointer so HJSONConfig copies share them
+type watchState struct {
+	mutex     sync.Mutex
+	callbacks []ChangeCallback
+}
+
+// watchers gives watch state making it if needed
+func (fl *HJSONConfig) watchers() *watchState {
+	fl.mutex.Lock()
+	defer fl.mutex.Unlock()
+	if nil == fl.watch {
+		fl.watch = &watchState{}
+	}
+	return fl.watch
+}
+
+// OnChange registers callback called after each successful reload made by Watch
+func (fl *HJSONConfig) OnChange(f ChangeCallback) {
+	if nil == f {
+		return
+	}
+	w := fl.watchers()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.callbacks = append(w.callbacks, f)
+}
+
+// Watch watches config file and reloads config when file changes.
+// Native file system notifications(inotify) are used if possible, polling otherwise.
+// Symlink swaps in file directory(e.g. Kubernetes ConfigMap ..data symlink) which change
+// file target make one reload per swap.
+// Rapid writes are debounced by WatchDebounce. New file is checked by CheckExternalConfig before
+// internal map is swapped, so broken file keeps previous map and error is sent to channel returned.
+// Errors are dropped if nobody reads them. Channel is closed when ctx is done
+func (fl *HJSONConfig) Watch(ctx context.Context) (<-chan error, error) {
+	filename := fl.getFilename()
+	if "" == filename {
+		return nil, NewConfigUsageError("Can not watch config cause it's not loaded from file")
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newNativeWatcher(filepath.Dir(abs))
+	if err != nil {
+		w = newPollWatcher(abs, WatchPollInterval)
+	}
+	return fl.watchWith(ctx, w, abs), nil
+}
+
+// resolveTarget gives real file path. empty string if it can not be resolved
+func resolveTarget(filename string) string {
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+// watchWith runs watch loop for watcher given
+// filename is absolute config file path
+func (fl *HJSONConfig) watchWith(ctx context.Context, w fileWatcher, filename string) <-chan error {
+	name := filepath.Base(filename)
+	target := resolveTarget(filename)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer w.Close()
+		var fire <-chan time.Time
+		var timer *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if nil != timer {
+					timer.Stop()
+				}
+				return
+			case changed, ok := <-w.Events():
+				if !ok {
+					return
+				}
+				current := resolveTarget(filename)
+				if changed != name {
+					// Kubernetes ConfigMap like mounts re-point symlinks(..data) in directory
+					// and do not touch file itself. Reload if file target is changed by them
+					if current == target {
+						continue
+					}
+				}
+				target = current
+				if nil != timer {
+					timer.Stop()
+				}
+				timer = time.NewTimer(WatchDebounce)
+				fire = timer.C
+			case <-fire:
+				fire = nil
+				if err := fl.watchReload(); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}
+	}()
+	return errs
+}
+
+// watchReload checks and reloads config file and calls callbacks
+func (fl *HJSONConfig) watchReload() error {
+	if err := fl.CheckExternalConfig(); err != nil {
+		return err
+	}
+	old := fl.snapshotOf(fl.getMap())
+	if err := fl.ReloadInternalMap(); err != nil {
+		return err
+	}
+	current := fl.snapshotOf(fl.getMap())
+	w := fl.watchers()
+	w.mutex.Lock()
+	callbacks := make([]ChangeCallback, len(w.callbacks))
+	copy(callbacks, w.callbacks)
+	w.mutex.Unlock()
+	for _, f := range callbacks {
+		f(old, current)
+	}
+	return nil
+}
+
+// pollWatcher is fileWatcher checking file modification time and size periodically
+type pollWatcher struct {
+	events chan string
+	done   chan struct{}
+	once   sync.Once
+}
+
+// newPollWatcher starts polling file
+func newPollWatcher(filename string, interval time.Duration) *pollWatcher {
+	w := &pollWatcher{events: make(chan string), done: make(chan struct{})}
+	name := filepath.Base(filename)
+	last := pollState(filename)
+	go func() {
+		defer close(w.events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				current := pollState(filename)
+				if current == last {
+					continue
+				}
+				last = current
+				select {
+				case w.events <- name:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// fileState is what polling watcher compares
+// contents hash is there cause file system time resolution is too rough for rapid writes
+type fileState struct {
+	modTime time.Time
+	size    int64
+	hash    uint64
+	exists  bool
+}
+
+// pollState gets file state. symlinks are followed
+func pollState(filename string) fileState {
+	st, err := os.Stat(filename)
+	if err != nil {
+		return fileState{}
+	}
+	cnt, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fileState{}
+	}
+	h := fnv.New64a()
+	h.Write(cnt)
+	return fileState{modTime: st.ModTime(), size: st.Size(), hash: h.Sum64(), exists: true}
+}
+
+// Events is fileWatcher method
+func (w *pollWatcher) Events() <-chan string {
+	return w.events
+}
+
+// Close is fileWatcher method
+func (w *pollWatcher) Close() error {
+	w.once.Do(func() { close(w.done) })
+	return nil
+}
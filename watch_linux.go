@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+package configuration
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask are directory events making file reload
+const inotifyMask = syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_CREATE |
+	syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ATTRIB
+
+// inotifyWatcher is fileWatcher built on linux inotify. It watches directory,
+// so files replaced by rename are watched too
+type inotifyWatcher struct {
+	file   *os.File
+	events chan string
+	once   sync.Once
+}
+
+// newNativeWatcher starts inotify watcher on directory
+func newNativeWatcher(dir string) (fileWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	if _, err = syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+	// non blocking descriptor makes os.File use runtime poller, so Close interrupts Read
+	w := &inotifyWatcher{file: os.NewFile(uintptr(fd), "inotify"), events: make(chan string)}
+	go w.read()
+	return w, nil
+}
+
+// read reads inotify events and sends names of files changed
+func (w *inotifyWatcher) read() {
+	defer close(w.events)
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + syscall.SizeofInotifyEvent
+			nameEnd := nameStart + int(ev.Len)
+			offset = nameEnd
+			if nameEnd > n {
+				break
+			}
+			name := string(buf[nameStart:nameEnd])
+			for len(name) > 0 && name[len(name)-1] == 0 {
+				name = name[:len(name)-1]
+			}
+			w.events <- name
+		}
+	}
+}
+
+// Events is fileWatcher method
+func (w *inotifyWatcher) Events() <-chan string {
+	return w.events
+}
+
+// Close is fileWatcher method
+func (w *inotifyWatcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		err = w.file.Close()
+		// drain events so reader is not blocked on send
+		go func() {
+			for range w.events {
+			}
+		}()
+	})
+	return err
+}
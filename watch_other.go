@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package configuration
+
+// newNativeWatcher is not implemented there, so Watch uses polling
+func newNativeWatcher(dir string) (fileWatcher, error) {
+	return nil, NewConfigNotImplementedError("Native file watcher is not implemented for this platform")
+}
@@ -0,0 +1,232 @@
+package configuration
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// watchTestSetup makes temp config file and config loaded from it
+func watchTestSetup(t *testing.T) (dir string, filename string, fl *HJSONConfig) {
+	dir, err := ioutil.TempDir("", "configuration-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename = filepath.Join(dir, "app.hjson")
+	if err = ioutil.WriteFile(filename, []byte("{\n  value: 1\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fl, err = NewHJSONConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, filename, fl
+}
+
+// watchTestWait waits for change callback
+func watchTestWait(t *testing.T, changes chan [2]int) [2]int {
+	select {
+	case c := <-changes:
+		return c
+	case <-time.After(5 * time.Second):
+		t.Fatal("change callback was not called")
+	}
+	return [2]int{}
+}
+
+func testHJSONConfigWatch(t *testing.T, start func(ctx context.Context, fl *HJSONConfig, filename string) (<-chan error, error)) {
+	dir, filename, fl := watchTestSetup(t)
+	defer os.RemoveAll(dir)
+	changes := make(chan [2]int, 10)
+	fl.OnChange(func(old, new IConfig) {
+		o, _ := old.GetIntValue("value")
+		n, _ := new.GetIntValue("value")
+		changes <- [2]int{o, n}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	errs, err := start(ctx, fl, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// rapid writes give one reload
+	for i := 2; i <= 5; i++ {
+		if err = ioutil.WriteFile(filename, []byte("{\n  value: "+string(rune('0'+i))+"\n}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c := watchTestWait(t, changes); !reflect.DeepEqual(c, [2]int{1, 5}) {
+		t.Errorf("change callback got %v, want [1 5]", c)
+	}
+	if i, _ := fl.GetIntValue("value"); i != 5 {
+		t.Errorf("HJSONConfig.GetIntValue() after reload = %v, want 5", i)
+	}
+	select {
+	case c := <-changes:
+		t.Errorf("change callback called once more with %v", c)
+	case <-time.After(3 * WatchDebounce):
+	}
+	// broken file keeps previous map
+	if err = ioutil.WriteFile(filename, []byte("{\n  value: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err = <-errs:
+		if nil == err {
+			t.Errorf("Watch() error channel is closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report broken file")
+	}
+	if i, _ := fl.GetIntValue("value"); i != 5 {
+		t.Errorf("HJSONConfig.GetIntValue() after broken file = %v, want 5", i)
+	}
+	// replacing file by rename is noticed too
+	tmp := filepath.Join(dir, "app.hjson.tmp")
+	if err = ioutil.WriteFile(tmp, []byte("{\n  value: 7\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Rename(tmp, filename); err != nil {
+		t.Fatal(err)
+	}
+	if c := watchTestWait(t, changes); !reflect.DeepEqual(c, [2]int{5, 7}) {
+		t.Errorf("change callback got %v, want [5 7]", c)
+	}
+	cancel()
+	select {
+	case _, ok := <-errs:
+		if ok {
+			t.Errorf("Watch() error channel got value after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Watch() error channel is not closed after cancel")
+	}
+}
+
+func TestHJSONConfig_Watch(t *testing.T) {
+	defer func(d time.Duration) { WatchDebounce = d }(WatchDebounce)
+	WatchDebounce = 50 * time.Millisecond
+	testHJSONConfigWatch(t, func(ctx context.Context, fl *HJSONConfig, filename string) (<-chan error, error) {
+		return fl.Watch(ctx)
+	})
+}
+
+func TestHJSONConfig_Watch_polling(t *testing.T) {
+	defer func(d time.Duration) { WatchDebounce = d }(WatchDebounce)
+	WatchDebounce = 50 * time.Millisecond
+	testHJSONConfigWatch(t, func(ctx context.Context, fl *HJSONConfig, filename string) (<-chan error, error) {
+		return fl.watchWith(ctx, newPollWatcher(filename, 10*time.Millisecond), filename), nil
+	})
+}
+
+func TestHJSONConfig_Watch_notFile(t *testing.T) {
+	fl := &HJSONConfig{filename: "", hjsonMap: map[string]interface{}{}}
+	_, err := fl.Watch(context.Background())
+	if err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+		t.Errorf("HJSONConfig.Watch() error = %v, want ConfigUsageError", err)
+	}
+}
+
+func TestHJSONConfig_watchReload_options(t *testing.T) {
+	dir, filename, fl := watchTestSetup(t)
+	defer os.RemoveAll(dir)
+	fl.SetStrictNumbers(true)
+	fl.SetTimeLayouts("2006-01-02")
+	called := false
+	fl.OnChange(func(old, new IConfig) {
+		called = true
+		if _, err := new.GetIntValue("fraction"); err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigTypeMismatchError" {
+			t.Errorf("new config GetIntValue() in strict mode error = %v, want ConfigTypeMismatchError", err)
+		}
+		if tm, err := new.GetTimeValue("date"); err != nil || tm.Year() != 2024 {
+			t.Errorf("new config GetTimeValue() with layout = %v, %v", tm, err)
+		}
+		for _, c := range []IConfig{old, new} {
+			if err := c.ReloadInternalMap(); err == nil || reflect.TypeOf(err).String() != "*configuration.ConfigUsageError" {
+				t.Errorf("callback config ReloadInternalMap() error = %v, want ConfigUsageError", err)
+			}
+		}
+	})
+	if err := ioutil.WriteFile(filename, []byte("{\n  fraction: 1.5\n  date: 2024-02-03\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fl.watchReload(); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Errorf("change callback was not called")
+	}
+}
+
+// configMapSwap makes new data directory and atomically re-points ..data symlink to it
+// the same way kubelet updates ConfigMap volumes
+func configMapSwap(t *testing.T, dir string, version string, cnt string) {
+	data := filepath.Join(dir, "..ts_"+version)
+	if err := os.Mkdir(data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(data, "app.hjson"), []byte(cnt), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old, _ := os.Readlink(filepath.Join(dir, "..data"))
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink("..ts_"+version, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if "" != old {
+		if err := os.RemoveAll(filepath.Join(dir, old)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHJSONConfig_Watch_symlinkSwap(t *testing.T) {
+	defer func(d time.Duration) { WatchDebounce = d }(WatchDebounce)
+	WatchDebounce = 50 * time.Millisecond
+	dir, err := ioutil.TempDir("", "configuration-configmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	configMapSwap(t, dir, "1", "{\n  value: 1\n}\n")
+	filename := filepath.Join(dir, "app.hjson")
+	if err = os.Symlink(filepath.Join("..data", "app.hjson"), filename); err != nil {
+		t.Fatal(err)
+	}
+	fl, err := NewHJSONConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := make(chan [2]int, 10)
+	fl.OnChange(func(old, new IConfig) {
+		o, _ := old.GetIntValue("value")
+		n, _ := new.GetIntValue("value")
+		changes <- [2]int{o, n}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err = fl.Watch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range [][2]int{{1, 2}, {2, 3}} {
+		configMapSwap(t, dir, string(rune('2'+i)), "{\n  value: "+string(rune('2'+i))+"\n}\n")
+		if c := watchTestWait(t, changes); !reflect.DeepEqual(c, want) {
+			t.Errorf("change callback got %v, want %v", c, want)
+		}
+		// exactly one reload per swap
+		select {
+		case c := <-changes:
+			t.Errorf("change callback called once more with %v", c)
+		case <-time.After(5 * WatchDebounce):
+		}
+	}
+	if i, _ := fl.GetIntValue("value"); i != 3 {
+		t.Errorf("HJSONConfig.GetIntValue() after swaps = %v, want 3", i)
+	}
+}
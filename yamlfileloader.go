@@ -0,0 +1,159 @@
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// YAMLConfig is configuration loader YAML interface
+// all getters are the same as HJSONConfig ones cause YAML data is normalized
+// to the same map[string]interface{}/float64 shape HJSON parser gives
+type YAMLConfig struct {
+	HJSONConfig
+}
+
+// yamlParser parses YAML contents for HJSONConfig internals
+type yamlParser struct{}
+
+// ParseStringContents parses YAML and normalizes result
+func (p yamlParser) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	var raw interface{}
+	err = yaml.Unmarshal(cnt, &raw)
+	if nil != err {
+		return nil, err
+	}
+	if nil == raw {
+		// empty document
+		return map[string]interface{}{}, nil
+	}
+	v := normalizeValue(raw)
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, NewConfigTypeMismatchError("YAML document root must be a mapping")
+	}
+	return m, nil
+}
+
+// ParseStringContents parses YAML - separated to method cause I want test that
+func (fl *YAMLConfig) ParseStringContents(cnt []byte) (m map[string]interface{}, err error) {
+	return yamlParser{}.ParseStringContents(cnt)
+}
+
+// SetDefaultLoadSetting sets default config file for loader
+// arguments are the same as HJSONConfig.SetDefaultLoadSetting has
+func (fl *YAMLConfig) SetDefaultLoadSetting(sl ...interface{}) (err error) {
+	fl.setParser(yamlParser{})
+	if len(sl) > 0 {
+		if v, ok := sl[0].(map[string]interface{}); ok {
+			// map given by programmer may contain YAML-like types too
+			return fl.HJSONConfig.SetDefaultLoadSetting(normalizeValue(v))
+		}
+	}
+	return fl.HJSONConfig.SetDefaultLoadSetting(sl...)
+}
+
+// NewYAMLConfig creates new object or gives error
+// all arguments are same as HJSONConfig.SetDefaultLoadSetting
+func NewYAMLConfig(sl ...interface{}) (fl *YAMLConfig, err error) {
+	fl = &YAMLConfig{}
+	err = fl.SetDefaultLoadSetting(sl...)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// normalizeValue converts values given by format parsers to the shape HJSON parser gives:
+// maps become map[string]interface{}, lists []interface{} and numbers float64.
+// Integers float64 can not keep exactly stay int64 or uint64, see normalizeInt
+func normalizeValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalizeValue(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = normalizeValue(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, val := range t {
+			l[i] = normalizeValue(val)
+		}
+		return l
+	case []map[string]interface{}:
+		// TOML arrays of tables
+		l := make([]interface{}, len(t))
+		for i, val := range t {
+			l[i] = normalizeValue(val)
+		}
+		return l
+	case int:
+		return normalizeInt(int64(t))
+	case int8:
+		return normalizeInt(int64(t))
+	case int16:
+		return normalizeInt(int64(t))
+	case int32:
+		return normalizeInt(int64(t))
+	case int64:
+		return normalizeInt(t)
+	case uint:
+		return normalizeUint(uint64(t))
+	case uint8:
+		return normalizeUint(uint64(t))
+	case uint16:
+		return normalizeUint(uint64(t))
+	case uint32:
+		return normalizeUint(uint64(t))
+	case uint64:
+		return normalizeUint(t)
+	case json.Number:
+		// HJSON numbers are decoded as text to keep big integers exact, range is checked by checkNumbers
+		n, _ := parseNumber(string(t))
+		return n
+	case float32:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+// maxExactInt is the biggest integer float64 keeps exactly
+const maxExactInt = 1 << 53
+
+// normalizeInt gives integer as float64 like HJSON parser does while float64 keeps it exactly.
+// Bigger integers stay int64 so integer getters do not lose precision
+func normalizeInt(i int64) interface{} {
+	if i >= -maxExactInt && i <= maxExactInt {
+		return float64(i)
+	}
+	return i
+}
+
+// normalizeUint is normalizeInt for unsigned integers
+func normalizeUint(u uint64) interface{} {
+	if u <= maxExactInt {
+		return float64(u)
+	}
+	return u
+}
+
+// parseNumber parses number text. Integers are normalized like normalizeInt does
+func parseNumber(s string) (interface{}, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return normalizeInt(i), nil
+	}
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return normalizeUint(u), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
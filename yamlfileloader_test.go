@@ -0,0 +1,190 @@
+package configuration
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestYAMLConfig_ParseStringContents(t *testing.T) {
+	type args struct {
+		cnt []byte
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantM       map[string]interface{}
+		wantErr     bool
+		wantErrType string
+	}
+	tests := []teststruct{
+		{
+			name:        "Wrong broken yaml",
+			args:        args{cnt: []byte("a: [b")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "",
+		},
+		{
+			name:        "root is not a mapping",
+			args:        args{cnt: []byte("- a\n- b\n")},
+			wantM:       nil,
+			wantErr:     true,
+			wantErrType: "*configuration.ConfigTypeMismatchError",
+		},
+		{
+			name:        "empty document",
+			args:        args{cnt: []byte("")},
+			wantM:       map[string]interface{}{},
+			wantErr:     false,
+			wantErrType: "",
+		},
+		{
+			name: "nested maps and numbers are normalized",
+			args: args{cnt: []byte("a:\n  b: 1\n  c: 1.5\n  d: [1, x]\n2: two\n")},
+			wantM: map[string]interface{}{
+				"a": map[string]interface{}{
+					"b": float64(1),
+					"c": float64(1.5),
+					"d": []interface{}{float64(1), "x"},
+				},
+				"2": "two",
+			},
+			wantErr:     false,
+			wantErrType: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fl := &YAMLConfig{}
+			gotM, err := fl.ParseStringContents(tt.args.cnt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("YAMLConfig.ParseStringContents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("YAMLConfig.ParseStringContents() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if !reflect.DeepEqual(gotM, tt.wantM) {
+				t.Errorf("YAMLConfig.ParseStringContents() = %v, want %v", gotM, tt.wantM)
+			}
+		})
+	}
+}
+
+func TestNewYAMLConfig(t *testing.T) {
+	type args struct {
+		sl []interface{}
+	}
+	type teststruct struct {
+		name        string
+		args        args
+		wantErr     bool
+		wantErrType string
+		checker     func(fl *YAMLConfig) bool
+	}
+	tests := []teststruct{
+		{
+			name:        "no arguments",
+			args:        args{sl: []interface{}{}},
+			wantErr:     true,
+			wantErrType: "*configuration.HJSONConfigError",
+		},
+		{
+			name:        "wrong argument type",
+			args:        args{sl: []interface{}{42}},
+			wantErr:     true,
+			wantErrType: "*configuration.HJSONConfigError",
+		},
+		{
+			name:        "not existing file",
+			args:        args{sl: []interface{}{"/yaml_not_existing_awful_filename"}},
+			wantErr:     true,
+			wantErrType: "*configuration.HJSONConfigError",
+		},
+		{
+			name:    "load from file",
+			args:    args{sl: []interface{}{"./test.yaml"}},
+			wantErr: false,
+			checker: func(fl *YAMLConfig) bool {
+				i, err := fl.GetIntValue("section1", "port")
+				if err != nil || i != 8080 {
+					t.Errorf("YAMLConfig.GetIntValue() = %v, %v, want 8080", i, err)
+					return false
+				}
+				b, err := fl.GetBooleanValue("section1", "enabled")
+				if err != nil || !b {
+					t.Errorf("YAMLConfig.GetBooleanValue() = %v, %v, want true", b, err)
+					return false
+				}
+				if err = fl.CheckExternalConfig(); err != nil {
+					t.Errorf("YAMLConfig.CheckExternalConfig() error = %v", err)
+					return false
+				}
+				if err = fl.ReloadInternalMap(); err != nil {
+					t.Errorf("YAMLConfig.ReloadInternalMap() error = %v", err)
+					return false
+				}
+				s, err := fl.GetStringValue("CONFIG_FILE")
+				if err != nil || s != "./configuration/config.yaml" {
+					t.Errorf("YAMLConfig.GetStringValue() = %v, %v after reload", s, err)
+					return false
+				}
+				return true
+			},
+		},
+		{
+			name:    "load from bytes",
+			args:    args{sl: []interface{}{[]byte("a:\n  b: 42\n")}},
+			wantErr: false,
+			checker: func(fl *YAMLConfig) bool {
+				c, err := fl.GetSubconfig("a")
+				if err != nil {
+					t.Errorf("YAMLConfig.GetSubconfig() error = %v", err)
+					return false
+				}
+				i, err := c.GetIntValue("b")
+				if err != nil || i != 42 {
+					t.Errorf("subconfig GetIntValue() = %v, %v, want 42", i, err)
+					return false
+				}
+				if err = fl.CheckExternalConfig(); err == nil {
+					t.Errorf("YAMLConfig.CheckExternalConfig() must fail on config not loaded from file")
+					return false
+				}
+				return true
+			},
+		},
+		{
+			name: "load from map with yaml types",
+			args: args{sl: []interface{}{map[string]interface{}{
+				"a": map[interface{}]interface{}{"b": 42},
+			}}},
+			wantErr: false,
+			checker: func(fl *YAMLConfig) bool {
+				i, err := fl.GetIntValue("a", "b")
+				if err != nil || i != 42 {
+					t.Errorf("YAMLConfig.GetIntValue() = %v, %v, want 42", i, err)
+					return false
+				}
+				return true
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFl, err := NewYAMLConfig(tt.args.sl...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewYAMLConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && ("" != tt.wantErrType) && (tt.wantErrType != reflect.TypeOf(err).String()) {
+				t.Errorf("NewYAMLConfig() error type = %v, wantErrType %v", reflect.TypeOf(err), tt.wantErrType)
+				return
+			}
+			if nil != tt.checker {
+				tt.checker(gotFl)
+			}
+		})
+	}
+}